@@ -0,0 +1,268 @@
+package clock
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AlarmID identifies an alarm registered with a Scheduler.
+type AlarmID int
+
+// Event is sent by a Scheduler each time an alarm goes off.
+type Event struct {
+	ID      AlarmID
+	Clock   Clock     // the time of day the alarm was set for
+	At      time.Time // when the alarm was due, which may be a little before it was sent
+	Snoozed bool      // whether this is a snoozed alarm going off again
+}
+
+// alarm is the Scheduler's record of one registered clock.
+type alarm struct {
+	id     AlarmID
+	clock  Clock
+	days   [7]bool // which weekdays the alarm rings on
+	next   time.Time
+	snooze time.Time // a one-off ring requested by Snooze; zero if there isn't one
+}
+
+// Scheduler rings alarms at set times of day and sends an Event on its channel
+// each time one goes off. Times are read in the Scheduler's location, so an
+// alarm set for 07:00 keeps ringing at 07:00 local time across daylight saving
+// changes. An alarm that falls into a skipped hour rings as long after the jump
+// as it was set past the hour, so 02:30 rings at 03:30 when 02:00 becomes 03:00.
+// One that falls into a repeated hour rings only the first time round.
+//
+// If the time source jumps past several due times at once, each alarm rings
+// once and then carries on from the new time.
+type Scheduler struct {
+	src    TimeSource
+	loc    *time.Location
+	events chan Event
+	wake   chan struct{}
+	done   chan struct{}
+	exited chan struct{}
+	stop   sync.Once
+
+	mu     sync.Mutex
+	alarms map[AlarmID]*alarm
+	lastID AlarmID
+}
+
+// NewScheduler starts a scheduler that reads the time from src and works out
+// wall times in loc. A nil loc means time.Local. Call Stop once it is no longer
+// needed.
+func NewScheduler(src TimeSource, loc *time.Location) *Scheduler {
+	if loc == nil {
+		loc = time.Local
+	}
+	s := &Scheduler{
+		src:    src,
+		loc:    loc,
+		events: make(chan Event),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+		alarms: make(map[AlarmID]*alarm),
+	}
+	go s.run()
+	return s
+}
+
+// Events returns the channel alarms are delivered on. It is closed by Stop.
+func (s *Scheduler) Events() <-chan Event {
+	return s.events
+}
+
+// Set registers an alarm for c, ringing only on the given weekdays, or every day
+// if none are given. It fails if any of days is not a weekday.
+func (s *Scheduler) Set(c Clock, days ...time.Weekday) (AlarmID, error) {
+	a := &alarm{clock: c}
+	for _, d := range days {
+		if d < time.Sunday || d > time.Saturday {
+			return 0, fmt.Errorf("clock: %d is not a weekday", int(d))
+		}
+		a.days[d] = true
+	}
+	if len(days) == 0 {
+		a.days = [7]bool{true, true, true, true, true, true, true}
+	}
+
+	s.mu.Lock()
+	s.lastID++
+	a.id = s.lastID
+	a.next = s.nextAfter(a, s.src.Now())
+	s.alarms[a.id] = a
+	s.mu.Unlock()
+
+	s.poke()
+	return a.id, nil
+}
+
+// Cancel removes an alarm, reporting whether it was registered.
+func (s *Scheduler) Cancel(id AlarmID) bool {
+	s.mu.Lock()
+	_, ok := s.alarms[id]
+	delete(s.alarms, id)
+	s.mu.Unlock()
+
+	s.poke()
+	return ok
+}
+
+// Snooze makes an alarm ring once more after d has passed, on top of its usual
+// schedule. Snoozing again replaces the earlier snooze. It reports whether the
+// alarm is registered.
+func (s *Scheduler) Snooze(id AlarmID, d time.Duration) bool {
+	s.mu.Lock()
+	a, ok := s.alarms[id]
+	if ok {
+		a.snooze = s.src.Now().Add(d)
+	}
+	s.mu.Unlock()
+
+	s.poke()
+	return ok
+}
+
+// Next returns when an alarm will next ring, reporting false if it isn't
+// registered.
+func (s *Scheduler) Next(id AlarmID) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.alarms[id]
+	if !ok {
+		return time.Time{}, false
+	}
+	if !a.snooze.IsZero() && a.snooze.Before(a.next) {
+		return a.snooze, true
+	}
+	return a.next, true
+}
+
+// Stop shuts the scheduler down and closes its Events channel. It is safe to
+// call more than once.
+func (s *Scheduler) Stop() {
+	s.stop.Do(func() { close(s.done) })
+	<-s.exited
+}
+
+// poke tells the run loop that the alarms have changed.
+func (s *Scheduler) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) run() {
+	defer close(s.exited)
+	defer close(s.events)
+
+	for {
+		var fire <-chan time.Time
+		var timer Timer
+		if next, ok := s.earliest(); ok {
+			timer = s.src.NewTimer(next.Sub(s.src.Now()))
+			fire = timer.C()
+		}
+
+		select {
+		case <-s.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-s.wake:
+			if timer != nil {
+				timer.Stop()
+			}
+		case <-fire:
+			for _, e := range s.due(s.src.Now()) {
+				select {
+				case s.events <- e:
+				case <-s.done:
+					return
+				}
+			}
+		}
+	}
+}
+
+// earliest returns the soonest time any alarm is due.
+func (s *Scheduler) earliest() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var soonest time.Time
+	for _, a := range s.alarms {
+		for _, t := range []time.Time{a.next, a.snooze} {
+			if !t.IsZero() && (soonest.IsZero() || t.Before(soonest)) {
+				soonest = t
+			}
+		}
+	}
+	return soonest, !soonest.IsZero()
+}
+
+// due collects the alarms that should have rung by now, in the order they were
+// due, and moves each of them on to its next ring.
+func (s *Scheduler) due(now time.Time) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []Event
+	for _, a := range s.alarms {
+		if !a.snooze.IsZero() && !a.snooze.After(now) {
+			events = append(events, Event{ID: a.id, Clock: a.clock, At: a.snooze, Snoozed: true})
+			a.snooze = time.Time{}
+		}
+		if !a.next.After(now) {
+			events = append(events, Event{ID: a.id, Clock: a.clock, At: a.next})
+			a.next = s.nextAfter(a, now)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].At.Equal(events[j].At) {
+			return events[i].At.Before(events[j].At)
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events
+}
+
+// nextAfter finds the first time strictly after t that the alarm should ring.
+func (s *Scheduler) nextAfter(a *alarm, t time.Time) time.Time {
+	local := t.In(s.loc)
+	y, m, d := local.Date()
+	// A week and a day is enough to reach any weekday after today's ring.
+	for i := 0; i <= 7; i++ {
+		// Noon is never touched by daylight saving, so it pins down the date.
+		day := time.Date(y, m, d+i, 12, 0, 0, 0, s.loc)
+		if !a.days[day.Weekday()] {
+			continue
+		}
+		ring := pastGap(a.clock.On(day, s.loc), a.clock, s.loc)
+		if ring.After(t) {
+			return ring
+		}
+	}
+	return time.Time{}
+}
+
+// pastGap makes sure ring, meant to show c in loc, is after a daylight saving
+// gap that c falls into rather than before it. time.Date doesn't promise which
+// way it resolves a time in a gap, so ring may show a wall time earlier than c,
+// in which case it is pushed on by the difference, or later, in which case it
+// is already past the jump.
+func pastGap(ring time.Time, c Clock, loc *time.Location) time.Time {
+	diff := c.Sub(FromTime(ring, loc))
+	if diff > 12*time.Hour {
+		diff -= 24 * time.Hour
+	}
+	if diff > 0 {
+		ring = ring.Add(diff)
+	}
+	return ring
+}
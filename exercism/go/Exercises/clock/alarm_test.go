@@ -0,0 +1,201 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// monday is 06:00 UTC on a Monday.
+var monday = time.Date(2021, time.March, 1, 6, 0, 0, 0, time.UTC)
+
+func expectEvent(t *testing.T, s *Scheduler, want Event) {
+	t.Helper()
+	select {
+	case got := <-s.Events():
+		if got.ID != want.ID || got.Clock != want.Clock || !got.At.Equal(want.At) || got.Snoozed != want.Snoozed {
+			t.Fatalf("got event %+v, want %+v", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no event, want %+v", want)
+	}
+}
+
+func expectQuiet(t *testing.T, s *Scheduler) {
+	t.Helper()
+	select {
+	case got := <-s.Events():
+		t.Fatalf("got event %+v, want none", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestSchedulerDaily(t *testing.T) {
+	src := NewManualTime(monday)
+	s := NewScheduler(src, time.UTC)
+	defer s.Stop()
+
+	seven := New(7, 0)
+	id, _ := s.Set(seven)
+
+	src.Advance(59 * time.Minute)
+	expectQuiet(t, s)
+
+	src.Advance(time.Minute)
+	expectEvent(t, s, Event{ID: id, Clock: seven, At: monday.Add(time.Hour)})
+
+	src.Advance(24 * time.Hour)
+	expectEvent(t, s, Event{ID: id, Clock: seven, At: monday.Add(25 * time.Hour)})
+
+	// Jumping several days rings once and then picks the schedule back up.
+	src.Advance(72 * time.Hour)
+	expectEvent(t, s, Event{ID: id, Clock: seven, At: monday.Add(49 * time.Hour)})
+	expectQuiet(t, s)
+	if next, _ := s.Next(id); !next.Equal(monday.Add(121 * time.Hour)) {
+		t.Errorf("Next() = %v, want %v", next, monday.Add(121*time.Hour))
+	}
+}
+
+func TestSchedulerWeekdays(t *testing.T) {
+	src := NewManualTime(monday)
+	s := NewScheduler(src, time.UTC)
+	defer s.Stop()
+
+	nine := New(9, 0)
+	weekend, _ := s.Set(nine, time.Saturday, time.Sunday)
+	friday, _ := s.Set(nine, time.Friday)
+
+	saturday := time.Date(2021, time.March, 6, 9, 0, 0, 0, time.UTC)
+	if next, ok := s.Next(weekend); !ok || !next.Equal(saturday) {
+		t.Errorf("Next(weekend) = %v, want %v", next, saturday)
+	}
+
+	src.Set(saturday.Add(-time.Hour))
+	expectEvent(t, s, Event{ID: friday, Clock: nine, At: saturday.Add(-24 * time.Hour)})
+	src.Set(saturday)
+	expectEvent(t, s, Event{ID: weekend, Clock: nine, At: saturday})
+	src.Advance(24 * time.Hour)
+	expectEvent(t, s, Event{ID: weekend, Clock: nine, At: saturday.Add(24 * time.Hour)})
+}
+
+func TestSchedulerCancelAndSnooze(t *testing.T) {
+	src := NewManualTime(monday)
+	s := NewScheduler(src, time.UTC)
+	defer s.Stop()
+
+	kept, _ := s.Set(New(6, 30))
+	dropped, _ := s.Set(New(6, 15))
+	if !s.Cancel(dropped) || s.Cancel(dropped) {
+		t.Error("Cancel should succeed exactly once")
+	}
+	if s.Snooze(dropped, time.Minute) {
+		t.Error("Snooze succeeded on a cancelled alarm")
+	}
+
+	src.Advance(30 * time.Minute)
+	ring := monday.Add(30 * time.Minute)
+	expectEvent(t, s, Event{ID: kept, Clock: New(6, 30), At: ring})
+
+	if !s.Snooze(kept, 9*time.Minute) {
+		t.Fatal("Snooze failed")
+	}
+	src.Advance(8 * time.Minute)
+	expectQuiet(t, s)
+	src.Advance(time.Minute)
+	expectEvent(t, s, Event{ID: kept, Clock: New(6, 30), At: ring.Add(9 * time.Minute), Snoozed: true})
+	expectQuiet(t, s)
+}
+
+func TestSchedulerDaylightSaving(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+
+	// Clocks in New York went forward at 02:00 on 14 March 2021 and back at
+	// 02:00 on 7 November 2021.
+	for _, tc := range []struct {
+		start time.Time
+		c     Clock
+		want  []time.Time
+	}{
+		{
+			time.Date(2021, time.March, 13, 12, 0, 0, 0, ny), New(7, 0),
+			[]time.Time{
+				time.Date(2021, time.March, 14, 11, 0, 0, 0, time.UTC),
+				time.Date(2021, time.March, 15, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			time.Date(2021, time.March, 13, 12, 0, 0, 0, ny), New(2, 30),
+			[]time.Time{
+				time.Date(2021, time.March, 14, 7, 30, 0, 0, time.UTC), // 03:30 EDT
+				time.Date(2021, time.March, 15, 6, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			time.Date(2021, time.November, 6, 12, 0, 0, 0, ny), New(1, 30),
+			[]time.Time{
+				time.Date(2021, time.November, 7, 5, 30, 0, 0, time.UTC), // 01:30 EDT
+				time.Date(2021, time.November, 8, 6, 30, 0, 0, time.UTC),
+			},
+		},
+	} {
+		src := NewManualTime(tc.start)
+		s := NewScheduler(src, ny)
+		id, _ := s.Set(tc.c)
+		for _, want := range tc.want {
+			if next, _ := s.Next(id); !next.Equal(want) {
+				t.Errorf("alarm for %v: Next() = %v, want %v", tc.c, next.UTC(), want)
+			}
+			src.Set(want)
+			expectEvent(t, s, Event{ID: id, Clock: tc.c, At: want})
+		}
+		s.Stop()
+	}
+}
+
+func TestSchedulerStop(t *testing.T) {
+	s := NewScheduler(NewManualTime(monday), nil)
+	s.Set(New(7, 0))
+	s.Stop()
+	s.Stop()
+	if _, open := <-s.Events(); open {
+		t.Error("Events channel still open after Stop")
+	}
+}
+
+func TestSchedulerBadWeekday(t *testing.T) {
+	s := NewScheduler(NewManualTime(monday), time.UTC)
+	defer s.Stop()
+	for _, d := range []time.Weekday{-1, 7, 100} {
+		if _, err := s.Set(New(7, 0), time.Monday, d); err == nil {
+			t.Errorf("Set with weekday %d succeeded", d)
+		}
+	}
+	if _, err := s.Set(New(7, 0), time.Sunday, time.Saturday); err != nil {
+		t.Errorf("Set with Sunday and Saturday: %v", err)
+	}
+}
+
+func TestPastGap(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	// 02:30 on 14 March 2021 doesn't exist in New York; it should ring at
+	// 03:30 EDT however the gap was resolved.
+	want := time.Date(2021, time.March, 14, 7, 30, 0, 0, time.UTC)
+	for _, ring := range []time.Time{
+		time.Date(2021, time.March, 14, 6, 30, 0, 0, time.UTC), // 01:30 EST
+		want,
+	} {
+		if got := pastGap(ring, New(2, 30), ny); !got.Equal(want) {
+			t.Errorf("pastGap(%v) = %v, want %v", ring, got.UTC(), want)
+		}
+	}
+	// Times outside a gap are left alone.
+	ring := time.Date(2021, time.March, 14, 11, 0, 0, 0, time.UTC)
+	if got := pastGap(ring, New(7, 0), ny); !got.Equal(ring) {
+		t.Errorf("pastGap moved 07:00 to %v", got.UTC())
+	}
+}
@@ -1,50 +1,116 @@
 /*
 Package clock implements a straightforward way of modifying the 24-hour time on
-a clock, as well as comparing the times on two different clocks.
+a clock, as well as comparing the times on two different clocks. Times are kept
+to the second, and can be moved to and from a time.Time in any time.Location.
+Ranges of the day, and alarms that ring at a given time, are built on top.
 */
 package clock
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
-// Define the Clock type here.
+const (
+	secondsPerMinute = 60
+	secondsPerHour   = 60 * secondsPerMinute
+	secondsPerDay    = 24 * secondsPerHour
+	minutesPerDay    = 24 * 60
+)
 
+// Clock is a time of day without a date. The zero Clock is midnight. Two clocks
+// showing the same time compare equal with ==.
 type Clock struct {
-	hours, minutes int
+	seconds int // seconds since midnight, always in [0, secondsPerDay)
 }
 
-// New Creates a new clock, but stores the time on it in minutes
+// New creates a new clock showing h hours and m minutes past midnight. Either
+// value may be negative or larger than a day; the result wraps around.
 func New(h, m int) Clock {
-	for m < 60 {
-		m += 60
-		h--
-	}
-	for m >= 60 {
-		m -= 60
-		h++
+	return NewHMS(h, m, 0)
+}
+
+// NewHMS creates a new clock showing h hours, m minutes and s seconds past
+// midnight, wrapping around the day in the same way New does.
+func NewHMS(h, m, s int) Clock {
+	// Reduce each part on its own first, so huge offsets can't overflow when
+	// they're scaled up to seconds.
+	total := (h%24)*secondsPerHour + (m%minutesPerDay)*secondsPerMinute + s%secondsPerDay
+	return Clock{wrap(total)}
+}
+
+// wrap folds any number of seconds into a single day.
+func wrap(s int) int {
+	s %= secondsPerDay
+	if s < 0 {
+		s += secondsPerDay
 	}
+	return s
+}
 
-	for h >= 24 {
-		h -= 24
+// FromTime returns the clock showing the wall time of t in loc. If loc is nil,
+// t's own location is used.
+func FromTime(t time.Time, loc *time.Location) Clock {
+	if loc != nil {
+		t = t.In(loc)
 	}
+	h, m, s := t.Clock()
+	return NewHMS(h, m, s)
+}
 
-	for h < 0 {
-		h += 24
+// On returns the time.Time at which the calendar date of date, as seen in loc,
+// shows the time on c. If loc is nil, date's own location is used. Times that
+// fall into a daylight saving gap are normalised the same way time.Date does.
+func (c Clock) On(date time.Time, loc *time.Location) time.Time {
+	if loc != nil {
+		date = date.In(loc)
 	}
+	y, mo, d := date.Date()
+	return time.Date(y, mo, d, c.Hour(), c.Minute(), c.Second(), 0, date.Location())
+}
 
-	return Clock{h, m}
+// Hour returns the hour on the clock, in [0, 23].
+func (c Clock) Hour() int {
+	return c.seconds / secondsPerHour
+}
+
+// Minute returns the minute on the clock, in [0, 59].
+func (c Clock) Minute() int {
+	return c.seconds % secondsPerHour / secondsPerMinute
+}
+
+// Second returns the second on the clock, in [0, 59].
+func (c Clock) Second() int {
+	return c.seconds % secondsPerMinute
 }
 
 // Add increments the number of minutes on a given clock
 func (c Clock) Add(m int) Clock {
-	return New(c.hours, c.minutes+m)
+	return NewHMS(0, m, c.seconds)
 }
 
 // Subtract decrements the number of minutes on a given clock
 func (c Clock) Subtract(m int) Clock {
-	return New(c.hours, c.minutes-m)
+	return NewHMS(0, -m, c.seconds)
+}
+
+// AddDuration moves the clock on by d, which may be negative. Anything finer
+// than a second is dropped.
+func (c Clock) AddDuration(d time.Duration) Clock {
+	return Clock{wrap(c.seconds + int(d/time.Second%secondsPerDay))}
+}
+
+// Sub returns how long it takes to get from u forward to c, wrapping past
+// midnight if needed. The result is always in [0, 24h).
+func (c Clock) Sub(u Clock) time.Duration {
+	return time.Duration(wrap(c.seconds-u.seconds)) * time.Second
 }
 
-// String returns the time on a clock face
+// String returns the time on a clock face. Seconds are only shown when they
+// aren't zero.
 func (c Clock) String() string {
-	return fmt.Sprintf("%02d:%02d", c.hours, c.minutes)
+	if s := c.Second(); s != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", c.Hour(), c.Minute(), s)
+	}
+	return fmt.Sprintf("%02d:%02d", c.Hour(), c.Minute())
 }
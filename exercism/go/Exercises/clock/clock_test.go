@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCreateClock(t *testing.T) {
@@ -108,6 +109,79 @@ func TestSubtractAndCompare(t *testing.T) {
 	}
 }
 
+func TestNewHMS(t *testing.T) {
+	for _, n := range []struct {
+		h, m, s int
+		want    string
+	}{
+		{8, 0, 0, "08:00"},
+		{8, 0, 30, "08:00:30"},
+		{23, 59, 60, "00:00"},
+		{0, 0, -1, "23:59:59"},
+		{0, 1e9, 0, "10:40"},
+		{-1e9, -1e9, -1e9, "19:33:20"},
+	} {
+		if got := NewHMS(n.h, n.m, n.s); got.String() != n.want {
+			t.Errorf("NewHMS(%d, %d, %d) = %q, want %q", n.h, n.m, n.s, got, n.want)
+		}
+	}
+}
+
+func TestAddDuration(t *testing.T) {
+	for _, a := range []struct {
+		c    Clock
+		d    time.Duration
+		want Clock
+	}{
+		{New(10, 0), 90 * time.Second, NewHMS(10, 1, 30)},
+		{New(23, 59), 2 * time.Minute, New(0, 1)},
+		{New(0, 0), -time.Second, NewHMS(23, 59, 59)},
+		{New(6, 0), 49 * time.Hour, New(7, 0)},
+		{New(6, 0), 1500 * time.Millisecond, NewHMS(6, 0, 1)},
+	} {
+		if got := a.c.AddDuration(a.d); got != a.want {
+			t.Errorf("%v.AddDuration(%v) = %v, want %v", a.c, a.d, got, a.want)
+		}
+	}
+}
+
+func TestSub(t *testing.T) {
+	for _, a := range []struct {
+		c, u Clock
+		want time.Duration
+	}{
+		{New(10, 0), New(9, 30), 30 * time.Minute},
+		{New(1, 0), New(23, 0), 2 * time.Hour},
+		{New(9, 30), New(10, 0), 23*time.Hour + 30*time.Minute},
+		{New(12, 0), New(12, 0), 0},
+	} {
+		if got := a.c.Sub(a.u); got != a.want {
+			t.Errorf("%v.Sub(%v) = %v, want %v", a.c, a.u, got, a.want)
+		}
+		if back := a.u.AddDuration(a.want); back != a.c {
+			t.Errorf("%v.AddDuration(%v) = %v, want %v", a.u, a.want, back, a.c)
+		}
+	}
+}
+
+func TestTimeConversion(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	instant := time.Date(2021, time.March, 14, 20, 15, 42, 0, time.UTC)
+
+	if got, want := FromTime(instant, nil), NewHMS(20, 15, 42); got != want {
+		t.Errorf("FromTime(%v, nil) = %v, want %v", instant, got, want)
+	}
+	if got, want := FromTime(instant, tokyo), NewHMS(5, 15, 42); got != want {
+		t.Errorf("FromTime(%v, JST) = %v, want %v", instant, got, want)
+	}
+
+	got := New(7, 30).On(instant, tokyo)
+	want := time.Date(2021, time.March, 15, 7, 30, 0, 0, tokyo)
+	if !got.Equal(want) || got.Location() != tokyo {
+		t.Errorf("New(7, 30).On(%v, JST) = %v, want %v", instant, got, want)
+	}
+}
+
 func BenchmarkAddMinutes(b *testing.B) {
 	if testing.Short() {
 		b.Skip("skipping benchmark in short mode.")
@@ -0,0 +1,66 @@
+package clock
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MarshalText encodes the clock the same way String does.
+func (c Clock) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText accepts any form that Parse does.
+func (c *Clock) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// MarshalJSON encodes the clock as a JSON string such as "15:04".
+func (c Clock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON decodes a JSON string in any form that Parse accepts. A JSON
+// null leaves the clock untouched, as is usual for encoding/json.
+func (c *Clock) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("clock: JSON value must be a string: %w", err)
+	}
+	return c.UnmarshalText([]byte(s))
+}
+
+// Value stores the clock as a "15:04:05" string, which SQL TIME columns accept.
+func (c Clock) Value() (driver.Value, error) {
+	return fmt.Sprintf("%02d:%02d:%02d", c.Hour(), c.Minute(), c.Second()), nil
+}
+
+// Scan reads a clock back from a database. Strings and byte slices go through
+// Parse, and time.Time values (as some drivers return for TIME columns) keep
+// their wall clock.
+func (c *Clock) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return c.UnmarshalText([]byte(v))
+	case []byte:
+		return c.UnmarshalText(v)
+	case time.Time:
+		*c = FromTime(v, nil)
+		return nil
+	case nil:
+		return errors.New("clock: cannot scan NULL into Clock")
+	default:
+		return fmt.Errorf("clock: cannot scan %T into Clock", src)
+	}
+}
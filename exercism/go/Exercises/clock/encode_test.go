@@ -0,0 +1,87 @@
+package clock
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+var (
+	_ encoding.TextMarshaler   = Clock{}
+	_ encoding.TextUnmarshaler = (*Clock)(nil)
+	_ json.Marshaler           = Clock{}
+	_ json.Unmarshaler         = (*Clock)(nil)
+	_ driver.Valuer            = Clock{}
+	_ sql.Scanner              = (*Clock)(nil)
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	type shift struct {
+		Start Clock  `json:"start"`
+		End   *Clock `json:"end"`
+	}
+	end := NewHMS(17, 30, 15)
+	in := shift{Start: New(9, 0), End: &end}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"start":"09:00","end":"17:30:15"}`; string(data) != want {
+		t.Fatalf("json.Marshal = %s, want %s", data, want)
+	}
+
+	var out shift
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Start != in.Start || out.End == nil || *out.End != end {
+		t.Errorf("json round trip = %+v, want %+v", out, in)
+	}
+
+	if err := json.Unmarshal([]byte(`{"start":"25:00"}`), &out); err == nil {
+		t.Error("json.Unmarshal accepted an out of range hour")
+	}
+	if err := json.Unmarshal([]byte(`{"start":900}`), &out); err == nil {
+		t.Error("json.Unmarshal accepted a number")
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	for _, c := range []Clock{New(0, 0), New(23, 59), NewHMS(1, 2, 3)} {
+		text, _ := c.MarshalText()
+		var got Clock
+		if err := got.UnmarshalText(text); err != nil || got != c {
+			t.Errorf("UnmarshalText(%q) = %v, %v, want %v", text, got, err, c)
+		}
+	}
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	c := New(8, 5)
+	v, err := c.Value()
+	if err != nil || v != "08:05:00" {
+		t.Fatalf("Value() = %v, %v, want 08:05:00", v, err)
+	}
+
+	for _, src := range []interface{}{
+		v,
+		[]byte("08:05:00"),
+		time.Date(0, 1, 1, 8, 5, 0, 0, time.UTC),
+	} {
+		var got Clock
+		if err := got.Scan(src); err != nil || got != c {
+			t.Errorf("Scan(%#v) = %v, %v, want %v", src, got, err, c)
+		}
+	}
+
+	for _, src := range []interface{}{nil, int64(3), "noon"} {
+		var got Clock
+		if err := got.Scan(src); err == nil {
+			t.Errorf("Scan(%#v) succeeded, want error", src)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError describes a string that Parse could not turn into a Clock.
+type ParseError struct {
+	Input string // the string as given to Parse
+	Msg   string // what was wrong with it
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("clock: cannot parse %q: %s", e.Input, e.Msg)
+}
+
+// Parse reads a time of day in one of the forms "15:04", "15:04:05", "3:04PM",
+// "3:04:05 pm", "T15:04" or "T15:04:05". The AM/PM marker may be in either case
+// and may be separated from the time by a single space.
+func Parse(s string) (Clock, error) {
+	fail := func(format string, args ...interface{}) (Clock, error) {
+		return Clock{}, &ParseError{Input: s, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	rest := s
+	if rest == "" {
+		return fail("empty string")
+	}
+
+	// ISO 8601 times may carry a leading designator, but never a meridiem.
+	iso := strings.HasPrefix(rest, "T")
+	rest = strings.TrimPrefix(rest, "T")
+
+	var meridiem string
+	if n := len(rest); n >= 2 {
+		switch strings.ToUpper(rest[n-2:]) {
+		case "AM", "PM":
+			meridiem = strings.ToUpper(rest[n-2:])
+			rest = strings.TrimSuffix(rest[:n-2], " ")
+		}
+	}
+	if iso && meridiem != "" {
+		return fail("ISO 8601 time cannot have an AM/PM marker")
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) < 2 {
+		return fail("missing minutes, want hh:mm or hh:mm:ss")
+	}
+	if len(parts) > 3 {
+		return fail("too many fields, want hh:mm or hh:mm:ss")
+	}
+
+	h, ok := digits(parts[0], 1, 2)
+	if !ok {
+		return fail("hour %q is not a one or two digit number", parts[0])
+	}
+	m, ok := digits(parts[1], 2, 2)
+	if !ok {
+		return fail("minute %q is not a two digit number", parts[1])
+	}
+	var sec int
+	if len(parts) == 3 {
+		if sec, ok = digits(parts[2], 2, 2); !ok {
+			return fail("second %q is not a two digit number", parts[2])
+		}
+	}
+
+	switch {
+	case meridiem == "" && h > 23:
+		return fail("hour %d out of range [0, 23]", h)
+	case meridiem != "" && (h < 1 || h > 12):
+		return fail("hour %d out of range [1, 12] for %s", h, meridiem)
+	case m > 59:
+		return fail("minute %d out of range [0, 59]", m)
+	case sec > 59:
+		return fail("second %d out of range [0, 59]", sec)
+	}
+
+	// 12AM is midnight and 12PM is noon.
+	if meridiem != "" {
+		h %= 12
+		if meridiem == "PM" {
+			h += 12
+		}
+	}
+	return NewHMS(h, m, sec), nil
+}
+
+// digits reads s as a plain decimal number made of between min and max ASCII
+// digits. Signs, spaces and anything else are rejected.
+func digits(s string, min, max int) (int, bool) {
+	if len(s) < min || len(s) > max {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		n = n*10 + int(s[i]-'0')
+	}
+	return n, true
+}
@@ -0,0 +1,67 @@
+package clock
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var parseTests = []struct {
+	in   string
+	want Clock
+}{
+	{"15:04", New(15, 4)},
+	{"00:00", New(0, 0)},
+	{"7:30", New(7, 30)},
+	{"15:04:05", NewHMS(15, 4, 5)},
+	{"3:04PM", New(15, 4)},
+	{"3:04 pm", New(15, 4)},
+	{"12:00AM", New(0, 0)},
+	{"12:00PM", New(12, 0)},
+	{"11:59:59 am", NewHMS(11, 59, 59)},
+	{"T15:04", New(15, 4)},
+	{"T23:59:59", NewHMS(23, 59, 59)},
+}
+
+var parseErrorTests = []struct {
+	in, msg string
+}{
+	{"", "empty string"},
+	{"15", "missing minutes"},
+	{"1:2:3:4", "too many fields"},
+	{"24:00", "hour 24 out of range"},
+	{"13:00PM", "hour 13 out of range [1, 12] for PM"},
+	{"0:00AM", "hour 0 out of range [1, 12] for AM"},
+	{"12:60", "minute 60 out of range"},
+	{"12:00:60", "second 60 out of range"},
+	{"12:5", `minute "5" is not a two digit number`},
+	{"-1:00", `hour "-1" is not a one or two digit number`},
+	{"12:00:1x", `second "1x" is not a two digit number`},
+	{"T3:04PM", "ISO 8601 time cannot have an AM/PM marker"},
+	{" 12:00", `hour " 12" is not`},
+}
+
+func TestParse(t *testing.T) {
+	for _, p := range parseTests {
+		got, err := Parse(p.in)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", p.in, err)
+		} else if got != p.want {
+			t.Errorf("Parse(%q) = %v, want %v", p.in, got, p.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, p := range parseErrorTests {
+		_, err := Parse(p.in)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("Parse(%q) error = %v, want *ParseError", p.in, err)
+			continue
+		}
+		if perr.Input != p.in || !strings.Contains(perr.Msg, p.msg) {
+			t.Errorf("Parse(%q) error = %v, want message containing %q", p.in, err, p.msg)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package clock
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Range is a span of the day that starts at one clock and runs up to, but not
+// including, another. A range may wrap past midnight, so 22:00-06:00 covers the
+// night. The zero Range is empty.
+type Range struct {
+	start  Clock
+	length int // seconds covered, in [0, secondsPerDay]
+}
+
+// NewRange returns the range from start up to end. When start and end are the
+// same the range covers the whole day, which is what "00:00-00:00" means in
+// opening hours.
+func NewRange(start, end Clock) Range {
+	length := end.seconds - start.seconds
+	if length <= 0 {
+		length += secondsPerDay
+	}
+	return Range{start, length}
+}
+
+// RangeFor returns the range that starts at start and lasts for d. Durations
+// below zero give an empty range and durations over a day are cut to one day.
+func RangeFor(start Clock, d time.Duration) Range {
+	switch {
+	case d <= 0:
+		return Range{}
+	case d >= 24*time.Hour:
+		return Range{start, secondsPerDay}
+	}
+	return Range{start, int(d / time.Second)}
+}
+
+// Start returns the first clock in the range.
+func (r Range) Start() Clock {
+	return r.start
+}
+
+// End returns the clock the range runs up to. For a full day this is the same
+// as Start.
+func (r Range) End() Clock {
+	return Clock{wrap(r.start.seconds + r.length)}
+}
+
+// Duration returns how much of the day the range covers.
+func (r Range) Duration() time.Duration {
+	return time.Duration(r.length) * time.Second
+}
+
+// IsEmpty reports whether the range covers no time at all.
+func (r Range) IsEmpty() bool {
+	return r.length == 0
+}
+
+// Contains reports whether c falls inside the range.
+func (r Range) Contains(c Clock) bool {
+	return wrap(c.seconds-r.start.seconds) < r.length
+}
+
+// Overlaps reports whether the two ranges share any time.
+func (r Range) Overlaps(o Range) bool {
+	return len(r.Intersect(o)) > 0
+}
+
+// Intersect returns the time covered by both ranges. Two ranges that both wrap
+// midnight can meet in two separate places, so the result holds between zero
+// and two ranges, in order of their start.
+func (r Range) Intersect(o Range) []Range {
+	return NewRangeSet(r).Intersect(NewRangeSet(o)).Ranges()
+}
+
+// Union returns the time covered by either range: a single range when they
+// overlap or touch, and both of them in order of their start when they don't.
+func (r Range) Union(o Range) []Range {
+	return NewRangeSet(r, o).Ranges()
+}
+
+// String returns the range as "22:00-06:00".
+func (r Range) String() string {
+	return r.start.String() + "-" + r.End().String()
+}
+
+// span is a piece of a RangeSet that doesn't cross midnight, measured in
+// seconds from midnight as [lo, hi).
+type span struct {
+	lo, hi int
+}
+
+// RangeSet is a collection of ranges with any overlaps merged away. The zero
+// RangeSet is empty.
+type RangeSet struct {
+	spans []span // sorted, non-overlapping and non-touching, within one day
+}
+
+// NewRangeSet returns the set covering every given range.
+func NewRangeSet(ranges ...Range) RangeSet {
+	var spans []span
+	for _, r := range ranges {
+		spans = append(spans, r.spans()...)
+	}
+	return RangeSet{merge(spans)}
+}
+
+// spans splits the range at midnight.
+func (r Range) spans() []span {
+	lo, hi := r.start.seconds, r.start.seconds+r.length
+	switch {
+	case r.length == 0:
+		return nil
+	case hi <= secondsPerDay:
+		return []span{{lo, hi}}
+	}
+	return []span{{lo, secondsPerDay}, {0, hi - secondsPerDay}}
+}
+
+// merge sorts spans and joins any that overlap or touch.
+func merge(spans []span) []span {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].lo < spans[j].lo })
+	var out []span
+	for _, s := range spans {
+		if n := len(out); n > 0 && s.lo <= out[n-1].hi {
+			if s.hi > out[n-1].hi {
+				out[n-1].hi = s.hi
+			}
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// Ranges returns the set as the fewest ranges that cover it, in order of their
+// start. A stretch running through midnight comes back as one wrapping range,
+// listed last.
+func (s RangeSet) Ranges() []Range {
+	spans := s.spans
+	var wrapped *span
+	if n := len(spans); n > 1 && spans[0].lo == 0 && spans[n-1].hi == secondsPerDay {
+		wrapped = &span{spans[n-1].lo, secondsPerDay + spans[0].hi}
+		spans = spans[1 : n-1]
+	}
+
+	var out []Range
+	for _, sp := range spans {
+		out = append(out, Range{Clock{sp.lo}, sp.hi - sp.lo})
+	}
+	if wrapped != nil {
+		out = append(out, Range{Clock{wrapped.lo}, wrapped.hi - wrapped.lo})
+	}
+	return out
+}
+
+// Union returns the time covered by either set.
+func (s RangeSet) Union(o RangeSet) RangeSet {
+	spans := make([]span, 0, len(s.spans)+len(o.spans))
+	spans = append(spans, s.spans...)
+	return RangeSet{merge(append(spans, o.spans...))}
+}
+
+// Intersect returns the time covered by both sets.
+func (s RangeSet) Intersect(o RangeSet) RangeSet {
+	var out []span
+	for i, j := 0, 0; i < len(s.spans) && j < len(o.spans); {
+		a, b := s.spans[i], o.spans[j]
+		lo, hi := a.lo, a.hi
+		if b.lo > lo {
+			lo = b.lo
+		}
+		if b.hi < hi {
+			hi = b.hi
+		}
+		if lo < hi {
+			out = append(out, span{lo, hi})
+		}
+		// Move past whichever span finishes first.
+		if a.hi < b.hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return RangeSet{out}
+}
+
+// Contains reports whether c falls inside any range of the set.
+func (s RangeSet) Contains(c Clock) bool {
+	i := sort.Search(len(s.spans), func(i int) bool { return s.spans[i].hi > c.seconds })
+	return i < len(s.spans) && s.spans[i].lo <= c.seconds
+}
+
+// NextOpen returns the first clock at or after c that falls inside the set,
+// looking up to a day ahead. It reports false when the set is empty.
+func (s RangeSet) NextOpen(c Clock) (Clock, bool) {
+	if len(s.spans) == 0 {
+		return Clock{}, false
+	}
+	i := sort.Search(len(s.spans), func(i int) bool { return s.spans[i].hi > c.seconds })
+	switch {
+	case i == len(s.spans):
+		// Nothing left today, so it's the first opening tomorrow.
+		return Clock{s.spans[0].lo}, true
+	case s.spans[i].lo <= c.seconds:
+		return c, true
+	}
+	return Clock{s.spans[i].lo}, true
+}
+
+// Duration returns how much of the day the set covers.
+func (s RangeSet) Duration() time.Duration {
+	total := 0
+	for _, sp := range s.spans {
+		total += sp.hi - sp.lo
+	}
+	return time.Duration(total) * time.Second
+}
+
+// IsEmpty reports whether the set covers no time at all.
+func (s RangeSet) IsEmpty() bool {
+	return len(s.spans) == 0
+}
+
+// String lists the ranges of the set, separated by commas.
+func (s RangeSet) String() string {
+	var parts []string
+	for _, r := range s.Ranges() {
+		parts = append(parts, r.String())
+	}
+	return strings.Join(parts, ", ")
+}
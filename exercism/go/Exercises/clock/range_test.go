@@ -0,0 +1,135 @@
+package clock
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// r is shorthand for a range between two whole hours.
+func r(from, to int) Range {
+	return NewRange(New(from, 0), New(to, 0))
+}
+
+func TestRangeBasics(t *testing.T) {
+	for _, tc := range []struct {
+		r    Range
+		want time.Duration
+		str  string
+	}{
+		{r(9, 17), 8 * time.Hour, "09:00-17:00"},
+		{r(22, 6), 8 * time.Hour, "22:00-06:00"},
+		{r(0, 0), 24 * time.Hour, "00:00-00:00"},
+		{RangeFor(New(23, 30), time.Hour), time.Hour, "23:30-00:30"},
+		{RangeFor(New(1, 0), 30*time.Hour), 24 * time.Hour, "01:00-01:00"},
+		{Range{}, 0, "00:00-00:00"},
+	} {
+		if got := tc.r.Duration(); got != tc.want {
+			t.Errorf("%v.Duration() = %v, want %v", tc.r, got, tc.want)
+		}
+		if got := tc.r.String(); got != tc.str {
+			t.Errorf("String() = %q, want %q", got, tc.str)
+		}
+	}
+	if !(Range{}).IsEmpty() || r(0, 0).IsEmpty() {
+		t.Error("only the zero Range should be empty")
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	for _, tc := range []struct {
+		r    Range
+		c    Clock
+		want bool
+	}{
+		{r(9, 17), New(9, 0), true},
+		{r(9, 17), New(16, 59), true},
+		{r(9, 17), New(17, 0), false},
+		{r(22, 6), New(23, 0), true},
+		{r(22, 6), New(0, 0), true},
+		{r(22, 6), New(5, 59), true},
+		{r(22, 6), New(6, 0), false},
+		{r(22, 6), New(12, 0), false},
+		{r(3, 3), New(12, 0), true},
+		{Range{}, New(0, 0), false},
+	} {
+		if got := tc.r.Contains(tc.c); got != tc.want {
+			t.Errorf("%v.Contains(%v) = %t, want %t", tc.r, tc.c, got, tc.want)
+		}
+	}
+}
+
+func TestRangeIntersectUnion(t *testing.T) {
+	for _, tc := range []struct {
+		a, b       Range
+		meet, join []Range
+	}{
+		{r(9, 17), r(12, 20), []Range{r(12, 17)}, []Range{r(9, 20)}},
+		{r(9, 12), r(12, 15), nil, []Range{r(9, 15)}},
+		{r(9, 10), r(11, 12), nil, []Range{r(9, 10), r(11, 12)}},
+		{r(22, 6), r(4, 8), []Range{r(4, 6)}, []Range{r(22, 8)}},
+		{r(22, 6), r(23, 2), []Range{r(23, 2)}, []Range{r(22, 6)}},
+		{r(22, 6), r(4, 23), []Range{r(4, 6), r(22, 23)}, []Range{r(0, 0)}},
+		{r(22, 6), r(20, 2), []Range{r(22, 2)}, []Range{r(20, 6)}},
+		{r(22, 2), r(10, 12), nil, []Range{r(10, 12), r(22, 2)}},
+	} {
+		if got := tc.a.Intersect(tc.b); !reflect.DeepEqual(got, tc.meet) {
+			t.Errorf("%v.Intersect(%v) = %v, want %v", tc.a, tc.b, got, tc.meet)
+		}
+		if got := tc.b.Intersect(tc.a); !reflect.DeepEqual(got, tc.meet) {
+			t.Errorf("%v.Intersect(%v) = %v, want %v", tc.b, tc.a, got, tc.meet)
+		}
+		if got := tc.a.Union(tc.b); !reflect.DeepEqual(got, tc.join) {
+			t.Errorf("%v.Union(%v) = %v, want %v", tc.a, tc.b, got, tc.join)
+		}
+		if got, want := tc.a.Overlaps(tc.b), tc.meet != nil; got != want {
+			t.Errorf("%v.Overlaps(%v) = %t, want %t", tc.a, tc.b, got, want)
+		}
+	}
+}
+
+func TestRangeSet(t *testing.T) {
+	hours := NewRangeSet(r(9, 12), r(11, 14), r(22, 2), r(1, 3), r(18, 19))
+	if got, want := hours.String(), "09:00-14:00, 18:00-19:00, 22:00-03:00"; got != want {
+		t.Fatalf("NewRangeSet(...) = %q, want %q", got, want)
+	}
+	if got, want := hours.Duration(), 11*time.Hour; got != want {
+		t.Errorf("Duration() = %v, want %v", got, want)
+	}
+
+	for _, tc := range []struct {
+		at, next Clock
+		open     bool
+	}{
+		{New(10, 0), New(10, 0), true},
+		{New(14, 0), New(18, 0), false},
+		{New(19, 30), New(22, 0), false},
+		{New(2, 59), New(2, 59), true},
+		{New(3, 0), New(9, 0), false},
+		{New(23, 59), New(23, 59), true},
+	} {
+		if got := hours.Contains(tc.at); got != tc.open {
+			t.Errorf("Contains(%v) = %t, want %t", tc.at, got, tc.open)
+		}
+		if got, ok := hours.NextOpen(tc.at); !ok || got != tc.next {
+			t.Errorf("NextOpen(%v) = %v, %t, want %v", tc.at, got, ok, tc.next)
+		}
+	}
+
+	late := NewRangeSet(r(8, 9))
+	if got, _ := late.NextOpen(New(20, 0)); got != New(8, 0) {
+		t.Errorf("NextOpen wrapping to tomorrow = %v, want 08:00", got)
+	}
+	if _, ok := (RangeSet{}).NextOpen(New(0, 0)); ok {
+		t.Error("empty RangeSet reported an opening")
+	}
+
+	both := hours.Intersect(NewRangeSet(r(13, 23)))
+	if got, want := both.String(), "13:00-14:00, 18:00-19:00, 22:00-23:00"; got != want {
+		t.Errorf("Intersect = %q, want %q", got, want)
+	}
+	all := hours.Union(NewRangeSet(r(14, 18), r(19, 22), r(3, 9)))
+	if got := all.Ranges(); !reflect.DeepEqual(got, []Range{r(0, 0)}) {
+		t.Errorf("Union = %v, want the whole day", got)
+	}
+}
@@ -0,0 +1,123 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// TimeSource tells a Scheduler what time it is and lets it wait. SystemTime
+// uses the real clock; ManualTime only moves when told to, which keeps tests
+// deterministic.
+type TimeSource interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer delivers the current time on C once its duration has passed, unless it
+// is stopped first.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// SystemTime is the TimeSource backed by the time package.
+type SystemTime struct{}
+
+// Now returns time.Now().
+func (SystemTime) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer wraps time.NewTimer.
+func (SystemTime) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time { return t.t.C }
+func (t systemTimer) Stop() bool          { return t.t.Stop() }
+
+// ManualTime is a TimeSource that stands still until Advance or Set is called.
+// Timers fire as soon as the time reaches their deadline.
+type ManualTime struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+// NewManualTime returns a ManualTime that starts at now.
+func NewManualTime(now time.Time) *ManualTime {
+	return &ManualTime{now: now}
+}
+
+// Now returns the time the source has been moved to.
+func (m *ManualTime) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// NewTimer returns a timer that fires once the source has been moved on by d.
+// A timer for zero or less fires straight away.
+func (m *ManualTime) NewTimer(d time.Duration) Timer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := &manualTimer{owner: m, deadline: m.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- m.now
+		return t
+	}
+	m.timers = append(m.timers, t)
+	return t
+}
+
+// Advance moves the source on by d and fires any timers that are now due.
+func (m *ManualTime) Advance(d time.Duration) {
+	m.mu.Lock()
+	now := m.now.Add(d)
+	m.mu.Unlock()
+	m.Set(now)
+}
+
+// Set moves the source to now and fires any timers that are now due. Moving it
+// backwards is allowed, as happens to a real clock being corrected.
+func (m *ManualTime) Set(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+	waiting := m.timers[:0]
+	for _, t := range m.timers {
+		if t.deadline.After(now) {
+			waiting = append(waiting, t)
+			continue
+		}
+		t.c <- now
+	}
+	m.timers = waiting
+}
+
+type manualTimer struct {
+	owner    *ManualTime
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *manualTimer) C() <-chan time.Time { return t.c }
+
+// Stop removes the timer from its source, reporting whether it was still
+// waiting to fire.
+func (t *manualTimer) Stop() bool {
+	m := t.owner
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, other := range m.timers {
+		if other == t {
+			m.timers = append(m.timers[:i], m.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+package matrix
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// These are the ways the arithmetic on Matrix and FloatMatrix can fail. Shape
+// problems come wrapped in a *DimensionError.
+var (
+	ErrDimensionMismatch = errors.New("dimensions don't match")
+	ErrNotSquare         = errors.New("matrix is not square")
+	ErrSingular          = errors.New("matrix is singular")
+)
+
+// DimensionError reports an operation given matrices of the wrong shape. B is
+// left as zero for operations on a single matrix.
+type DimensionError struct {
+	Op   string
+	A, B [2]int // rows and columns of each operand
+	Err  error
+}
+
+func (e *DimensionError) Error() string {
+	if e.B == [2]int{} {
+		return fmt.Sprintf("matrix: %s: %dx%d: %v", e.Op, e.A[0], e.A[1], e.Err)
+	}
+	return fmt.Sprintf("matrix: %s: %dx%d and %dx%d: %v",
+		e.Op, e.A[0], e.A[1], e.B[0], e.B[1], e.Err)
+}
+
+func (e *DimensionError) Unwrap() error {
+	return e.Err
+}
+
+// Dims returns the number of rows and columns in m.
+func (m *Matrix) Dims() (rows, cols int) {
+	if len(*m) == 0 {
+		return 0, 0
+	}
+	return len(*m), len((*m)[0])
+}
+
+// zeros makes a rows x cols matrix of zeroes.
+func zeros(rows, cols int) Matrix {
+	m := make(Matrix, rows)
+	for i := range m {
+		m[i] = make([]int, cols)
+	}
+	return m
+}
+
+// Transpose returns a new matrix with the rows and columns of m swapped.
+func (m *Matrix) Transpose() *Matrix {
+	t := Matrix(m.Cols())
+	return &t
+}
+
+// Add returns the element-wise sum of m and o, which must be the same shape.
+func (m *Matrix) Add(o *Matrix) (*Matrix, error) {
+	r, c := m.Dims()
+	if or, oc := o.Dims(); r != or || c != oc {
+		return nil, &DimensionError{"Add", [2]int{r, c}, [2]int{or, oc}, ErrDimensionMismatch}
+	}
+	sum := zeros(r, c)
+	for i := range sum {
+		for j := range sum[i] {
+			sum[i][j] = (*m)[i][j] + (*o)[i][j]
+		}
+	}
+	return &sum, nil
+}
+
+// Mul returns the matrix product m × o. m must have as many columns as o has
+// rows.
+func (m *Matrix) Mul(o *Matrix) (*Matrix, error) {
+	r, n := m.Dims()
+	or, c := o.Dims()
+	if n != or {
+		return nil, &DimensionError{"Mul", [2]int{r, n}, [2]int{or, c}, ErrDimensionMismatch}
+	}
+	prod := zeros(r, c)
+	for i := 0; i < r; i++ {
+		// Walking o row by row keeps the inner loop on contiguous memory.
+		for k := 0; k < n; k++ {
+			a := (*m)[i][k]
+			if a == 0 {
+				continue
+			}
+			for j, b := range (*o)[k] {
+				prod[i][j] += a * b
+			}
+		}
+	}
+	return &prod, nil
+}
+
+// ScalarMul returns m with every element multiplied by k.
+func (m *Matrix) ScalarMul(k int) *Matrix {
+	r, c := m.Dims()
+	prod := zeros(r, c)
+	for i := range prod {
+		for j := range prod[i] {
+			prod[i][j] = k * (*m)[i][j]
+		}
+	}
+	return &prod
+}
+
+// Determinant returns the determinant of a square matrix, worked out exactly
+// with Bareiss elimination. If the determinant itself is too big for an int,
+// it fails with ErrOverflow.
+func (m *Matrix) Determinant() (int, error) {
+	r, c := m.Dims()
+	if r != c {
+		return 0, &DimensionError{Op: "Determinant", A: [2]int{r, c}, Err: ErrNotSquare}
+	}
+	_, det := m.echelon()
+	if !det.IsInt64() || int64(int(det.Int64())) != det.Int64() {
+		return 0, fmt.Errorf("matrix: Determinant: %w", ErrOverflow)
+	}
+	return int(det.Int64()), nil
+}
+
+// Rank returns the number of linearly independent rows in m. Like Determinant
+// it uses exact, fraction-free elimination, and works on any shape of matrix.
+func (m *Matrix) Rank() int {
+	rank, _ := m.echelon()
+	return rank
+}
+
+// echelon reduces a copy of m to row echelon form with Bareiss's fraction-free
+// elimination, and returns its rank and, if m is square, its determinant. Each
+// pivot is a minor of m, but the products worked out on the way to the next
+// one can be far bigger, so the sums are done in int while they fit and done
+// again with math/big if they don't.
+func (m *Matrix) echelon() (rank int, det *big.Int) {
+	rows, cols := m.Dims()
+	if rank, sign, pivot, ok := echelonInt(Matrix(m.Rows())); ok {
+		det = big.NewInt(0)
+		if rows == cols && rank == rows {
+			det.SetInt64(int64(sign * pivot))
+		}
+		return rank, det
+	}
+
+	a := make([][]*big.Int, rows)
+	for i, row := range *m {
+		a[i] = make([]*big.Int, cols)
+		for j, v := range row {
+			a[i][j] = big.NewInt(int64(v))
+		}
+	}
+	rank, sign, pivot := echelonBig(a)
+	det = big.NewInt(0)
+	if rows == cols && rank == rows {
+		det.Mul(pivot, big.NewInt(int64(sign)))
+	}
+	return rank, det
+}
+
+// echelonInt does the elimination for echelon in a, in place. It returns the
+// rank, the sign the row swaps give the determinant, and the last pivot, which
+// is 1 if there were none. ok is false if anything overflowed.
+func echelonInt(a Matrix) (rank, sign, pivot int, ok bool) {
+	rows, cols := a.Dims()
+	sign, pivot = 1, 1
+	for c := 0; c < cols && rank < rows; c++ {
+		p := rank
+		for p < rows && a[p][c] == 0 {
+			p++
+		}
+		if p == rows {
+			continue
+		}
+		if p != rank {
+			a[rank], a[p] = a[p], a[rank]
+			sign = -sign
+		}
+		for i := rank + 1; i < rows; i++ {
+			for j := c + 1; j < cols; j++ {
+				x, ok := mulSub(a[i][j], a[rank][c], a[i][c], a[rank][j])
+				if !ok || (x == minInt && pivot == -1) {
+					return 0, 0, 0, false
+				}
+				a[i][j] = x / pivot
+			}
+			a[i][c] = 0
+		}
+		pivot = a[rank][c]
+		rank++
+	}
+	return rank, sign, pivot, true
+}
+
+// echelonBig is echelonInt with numbers that can't overflow.
+func echelonBig(a [][]*big.Int) (rank, sign int, pivot *big.Int) {
+	rows := len(a)
+	cols := 0
+	if rows > 0 {
+		cols = len(a[0])
+	}
+	sign, pivot = 1, big.NewInt(1)
+	var t big.Int
+	for c := 0; c < cols && rank < rows; c++ {
+		p := rank
+		for p < rows && a[p][c].Sign() == 0 {
+			p++
+		}
+		if p == rows {
+			continue
+		}
+		if p != rank {
+			a[rank], a[p] = a[p], a[rank]
+			sign = -sign
+		}
+		for i := rank + 1; i < rows; i++ {
+			for j := c + 1; j < cols; j++ {
+				a[i][j].Mul(a[i][j], a[rank][c])
+				a[i][j].Sub(a[i][j], t.Mul(a[i][c], a[rank][j]))
+				a[i][j].Quo(a[i][j], pivot)
+			}
+			a[i][c].SetInt64(0)
+		}
+		pivot = a[rank][c]
+		rank++
+	}
+	return rank, sign, pivot
+}
+
+// minInt is the smallest value an int can hold.
+const minInt = -maxInt - 1
+
+// mulSub returns a*b - c*d, reporting false if it, or either product, is too
+// big for an int.
+func mulSub(a, b, c, d int) (int, bool) {
+	x, okX := mul(a, b)
+	y, okY := mul(c, d)
+	diff := x - y
+	return diff, okX && okY && (diff < x) == (y > 0)
+}
+
+// mul returns a*b, reporting false if it is too big for an int.
+func mul(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	p := a * b
+	if (a == -1 && b == minInt) || (b == -1 && a == minInt) || p/b != a {
+		return 0, false
+	}
+	return p, true
+}
+
+// Inverse returns the inverse of a square matrix. The inverse of an integer
+// matrix is rarely made of integers, so it comes back as a FloatMatrix.
+func (m *Matrix) Inverse() (*FloatMatrix, error) {
+	r, c := m.Dims()
+	if r != c {
+		return nil, &DimensionError{Op: "Inverse", A: [2]int{r, c}, Err: ErrNotSquare}
+	}
+	// Checking the exact rank first means near-singular integer matrices are
+	// never mistaken for singular ones, or the other way round.
+	if m.Rank() < r {
+		return nil, fmt.Errorf("matrix: Inverse: %w", ErrSingular)
+	}
+	return m.Float().inverse(0)
+}
+
+// Float returns a copy of m with every element converted to float64.
+func (m *Matrix) Float() *FloatMatrix {
+	f := make(FloatMatrix, len(*m))
+	for i, row := range *m {
+		f[i] = make([]float64, len(row))
+		for j, v := range row {
+			f[i][j] = float64(v)
+		}
+	}
+	return &f
+}
@@ -0,0 +1,191 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func mustNew(t testing.TB, s string) *Matrix {
+	t.Helper()
+	m, err := New(s)
+	if err != nil {
+		t.Fatalf("New(%q) returned error: %v", s, err)
+	}
+	return m
+}
+
+func TestTransposeAddScalar(t *testing.T) {
+	m := mustNew(t, "1 2 3\n4 5 6")
+	if got, want := *m.Transpose(), (Matrix{{1, 4}, {2, 5}, {3, 6}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Transpose() = %v, want %v", got, want)
+	}
+	if got, want := *m.ScalarMul(-2), (Matrix{{-2, -4, -6}, {-8, -10, -12}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ScalarMul(-2) = %v, want %v", got, want)
+	}
+	sum, err := m.Add(mustNew(t, "10 20 30\n40 50 60"))
+	if want := (Matrix{{11, 22, 33}, {44, 55, 66}}); err != nil || !reflect.DeepEqual(*sum, want) {
+		t.Errorf("Add() = %v, %v, want %v", sum, err, want)
+	}
+	if _, err := m.Add(m.Transpose()); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Add() of 2x3 and 3x2 error = %v, want ErrDimensionMismatch", err)
+	}
+}
+
+func TestMul(t *testing.T) {
+	a := mustNew(t, "1 2 3\n4 5 6")
+	b := mustNew(t, "7 8\n9 10\n11 12")
+	prod, err := a.Mul(b)
+	if want := (Matrix{{58, 64}, {139, 154}}); err != nil || !reflect.DeepEqual(*prod, want) {
+		t.Errorf("Mul() = %v, %v, want %v", prod, err, want)
+	}
+
+	_, err = a.Mul(a)
+	var dimErr *DimensionError
+	if !errors.As(err, &dimErr) || !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("Mul() of 2x3 and 2x3 error = %v, want *DimensionError", err)
+	}
+	if dimErr.Op != "Mul" || dimErr.A != [2]int{2, 3} || dimErr.B != [2]int{2, 3} {
+		t.Errorf("Mul() error = %+v, want Op Mul with shapes 2x3 and 2x3", dimErr)
+	}
+}
+
+func TestDeterminantAndRank(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		det  int
+		rank int
+	}{
+		{"5", 5, 1},
+		{"1 2\n3 4", -2, 2},
+		{"0 1\n1 0", -1, 2},
+		{"2 0 1\n1 3 2\n1 1 2", 6, 3},
+		{"1 2 3\n4 5 6\n7 8 9", 0, 2},
+		{"0 0 0\n0 0 0\n0 0 0", 0, 0},
+		{"0 2 1 3\n1 0 0 2\n2 1 0 1\n1 3 2 0", -11, 4},
+		{"1 2 3 4\n2 4 6 8\n0 0 1 1\n1 2 4 5", 0, 2},
+	} {
+		m := mustNew(t, test.in)
+		if det, err := m.Determinant(); err != nil || det != test.det {
+			t.Errorf("New(%q).Determinant() = %d, %v, want %d", test.in, det, err, test.det)
+		}
+		if rank := m.Rank(); rank != test.rank {
+			t.Errorf("New(%q).Rank() = %d, want %d", test.in, rank, test.rank)
+		}
+		if fdet, _ := m.Float().Determinant(); math.Abs(fdet-float64(test.det)) > 1e-9 {
+			t.Errorf("New(%q).Float().Determinant() = %g, want %d", test.in, fdet, test.det)
+		}
+		if rank := m.Float().Rank(); rank != test.rank {
+			t.Errorf("New(%q).Float().Rank() = %d, want %d", test.in, rank, test.rank)
+		}
+	}
+
+	if rank := mustNew(t, "1 2 3 4\n2 4 6 9").Rank(); rank != 2 {
+		t.Errorf("Rank() of a wide matrix = %d, want 2", rank)
+	}
+	if _, err := mustNew(t, "1 2 3").Determinant(); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("Determinant() of 1x3 error = %v, want ErrNotSquare", err)
+	}
+}
+
+func TestDeterminantOverflow(t *testing.T) {
+	// The determinant fits in an int, but the products on the way to it
+	// don't.
+	m := Matrix{{1 << 31, 1, 0}, {0, 1 << 9, 1}, {0, 0, 1 << 9}}
+	if det, err := m.Determinant(); err != nil || det != 1<<49 {
+		t.Errorf("Determinant() = %d, %v, want %d", det, err, 1<<49)
+	}
+	if rank := m.Rank(); rank != 3 {
+		t.Errorf("Rank() = %d, want 3", rank)
+	}
+	inv, err := m.Inverse()
+	if err != nil {
+		t.Fatalf("Inverse() error = %v", err)
+	}
+	if want, _ := m.Float().Inverse(); !closeTo(*inv, *want) {
+		t.Errorf("Inverse() = %v, want %v", *inv, *want)
+	}
+
+	// Here the determinant itself doesn't fit.
+	m = Matrix{{1 << 40, 1}, {0, 1 << 40}}
+	if _, err := m.Determinant(); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Determinant() error = %v, want ErrOverflow", err)
+	}
+	if rank := m.Rank(); rank != 2 {
+		t.Errorf("Rank() = %d, want 2", rank)
+	}
+	if _, err := m.Inverse(); err != nil {
+		t.Errorf("Inverse() error = %v", err)
+	}
+}
+
+func closeTo(a, b [][]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if math.Abs(a[i][j]-b[i][j]) > 1e-9 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestInverse(t *testing.T) {
+	m := mustNew(t, "4 7\n2 6")
+	inv, err := m.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{0.6, -0.7}, {-0.2, 0.4}}; !closeTo(*inv, want) {
+		t.Errorf("Inverse() = %v, want %v", *inv, want)
+	}
+
+	m = mustNew(t, "2 0 1\n1 3 2\n1 1 2")
+	inv, err = m.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ident, _ := m.Float().Mul(inv)
+	if want := [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}; !closeTo(*ident, want) {
+		t.Errorf("m × m.Inverse() = %v, want identity", *ident)
+	}
+	back, err := inv.Inverse()
+	if err != nil || !closeTo(*back, *m.Float()) {
+		t.Errorf("Inverse().Inverse() = %v, %v, want %v", back, err, *m)
+	}
+
+	if _, err := mustNew(t, "1 2 3\n4 5 6\n7 8 9").Inverse(); !errors.Is(err, ErrSingular) {
+		t.Errorf("Inverse() of singular matrix error = %v, want ErrSingular", err)
+	}
+	f := FloatMatrix{{1, 2}, {0.5, 1}}
+	if _, err := f.Inverse(); !errors.Is(err, ErrSingular) {
+		t.Errorf("FloatMatrix.Inverse() of singular matrix error = %v, want ErrSingular", err)
+	}
+	if _, err := mustNew(t, "1 2").Inverse(); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("Inverse() of 1x2 error = %v, want ErrNotSquare", err)
+	}
+}
+
+func TestFloatMatrix(t *testing.T) {
+	f := FloatMatrix{{1.5, 2}, {3, 4.25}}
+	if got := f.Cols(); !reflect.DeepEqual(got, [][]float64{{1.5, 3}, {2, 4.25}}) {
+		t.Errorf("Cols() = %v", got)
+	}
+	if f.Set(2, 0, 1) || !f.Set(1, 1, 9) || f[1][1] != 9 {
+		t.Errorf("Set() did not bounds-check and update: %v", f)
+	}
+	sum, err := f.Add(f.ScalarMul(-1))
+	if err != nil || !closeTo(*sum, [][]float64{{0, 0}, {0, 0}}) {
+		t.Errorf("f + -f = %v, %v, want zeroes", sum, err)
+	}
+	if _, err := f.Mul(&FloatMatrix{{1, 2, 3}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Mul() of 2x2 and 1x3 error = %v, want ErrDimensionMismatch", err)
+	}
+}
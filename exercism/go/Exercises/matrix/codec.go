@@ -0,0 +1,498 @@
+package matrix
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format is a way of writing a matrix down, for Decode and Encode.
+type Format int
+
+const (
+	// Text is the format New reads: one row per line, values separated by
+	// spaces.
+	Text Format = iota
+	// CSV is one row per line, values separated by commas. Values may be
+	// wrapped in double quotes.
+	CSV
+	// TSV is one row per line, values separated by tabs.
+	TSV
+	// MatrixMarket is the NIST .mtx exchange format. Decode reads integer and
+	// pattern matrices in coordinate or array layout, with general, symmetric
+	// or skew-symmetric storage.
+	MatrixMarket
+)
+
+func (f Format) String() string {
+	switch f {
+	case Text:
+		return "Text"
+	case CSV:
+		return "CSV"
+	case TSV:
+		return "TSV"
+	case MatrixMarket:
+		return "MatrixMarket"
+	}
+	return "Format(" + strconv.Itoa(int(f)) + ")"
+}
+
+// These are the extra ways Decode and Encode can fail, on top of the errors New
+// can give. All but ErrFormat come from Matrix Market input.
+var (
+	ErrFormat   = errors.New("unknown format")
+	ErrHeader   = errors.New("bad Matrix Market header")
+	ErrSizeLine = errors.New("bad Matrix Market size line")
+	ErrIndex    = errors.New("index outside the matrix")
+	ErrCount    = errors.New("wrong number of entries")
+)
+
+// lineReader hands out one line at a time, keeping count of where it is. Lines
+// can be any length.
+type lineReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// next returns the following line without its line ending, or io.EOF once the
+// input is used up.
+func (lr *lineReader) next() (string, error) {
+	s, err := lr.r.ReadString('\n')
+	if err == io.EOF && s == "" {
+		return "", io.EOF
+	}
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	lr.line++
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r"), nil
+}
+
+// Decode reads a matrix from r one line at a time, so only the matrix itself
+// has to fit in memory. Text, CSV and TSV follow the same rules as New, except
+// that the input may end with a newline. Matrix Market input in coordinate
+// layout comes back as a *Sparse, and everything else as a *Matrix.
+//
+// Errors in the input are reported as a *ParseError giving the line, and the
+// value along the line, where they were found.
+func Decode(r io.Reader, f Format) (Interface, error) {
+	lr := &lineReader{r: bufio.NewReaderSize(r, 64*1024)}
+	switch f {
+	case Text:
+		return decodeDelimited(lr, strings.Fields)
+	case CSV:
+		return decodeDelimited(lr, splitter(",", `"`))
+	case TSV:
+		return decodeDelimited(lr, splitter("\t", ""))
+	case MatrixMarket:
+		return decodeMarket(lr)
+	}
+	return nil, fmt.Errorf("matrix: %w %v", ErrFormat, f)
+}
+
+// splitter returns a function that splits a line on sep and trims spaces, and
+// then any quote characters, from each value.
+func splitter(sep, quote string) func(string) []string {
+	return func(line string) []string {
+		if strings.TrimSpace(line) == "" {
+			return nil
+		}
+		fields := strings.Split(line, sep)
+		for i, f := range fields {
+			f = strings.TrimSpace(f)
+			if quote != "" && len(f) >= 2 && strings.HasPrefix(f, quote) && strings.HasSuffix(f, quote) {
+				f = f[1 : len(f)-1]
+			}
+			fields[i] = f
+		}
+		return fields
+	}
+}
+
+func decodeDelimited(lr *lineReader, split func(string) []string) (*Matrix, error) {
+	var m Matrix
+	for {
+		line, err := lr.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		width := -1
+		if len(m) > 0 {
+			width = len(m[0])
+		}
+		row, err := parseRow(lr.line, split(line), width)
+		if err != nil {
+			return nil, err
+		}
+		m = append(m, row)
+	}
+	if len(m) == 0 {
+		return nil, &ParseError{Line: 1, Err: ErrEmptyRow}
+	}
+	return &m, nil
+}
+
+// marketHeader is what the first line of a Matrix Market file says about the
+// rest of it.
+type marketHeader struct {
+	coordinate bool // entries are listed by position, not one for every element
+	pattern    bool // entries have no value; every listed element is 1
+	symmetry   string
+}
+
+func parseMarketHeader(line string) (marketHeader, error) {
+	var h marketHeader
+	fields := strings.Fields(strings.ToLower(line))
+	fail := func(col int, format string, args ...interface{}) (marketHeader, error) {
+		return h, &ParseError{Line: 1, Col: col, Err: fmt.Errorf("%w: %s", ErrHeader, fmt.Sprintf(format, args...))}
+	}
+	if len(fields) != 5 || fields[0] != "%%matrixmarket" {
+		return fail(0, "want %q", "%%MatrixMarket matrix <layout> <field> <symmetry>")
+	}
+	if fields[1] != "matrix" {
+		return fail(2, "object %q is not matrix", fields[1])
+	}
+	switch fields[2] {
+	case "coordinate":
+		h.coordinate = true
+	case "array":
+	default:
+		return fail(3, "unknown layout %q", fields[2])
+	}
+	switch fields[3] {
+	case "integer":
+	case "pattern":
+		h.pattern = true
+	default:
+		return fail(4, "field %q is not integer or pattern", fields[3])
+	}
+	switch fields[4] {
+	case "general", "symmetric", "skew-symmetric":
+		h.symmetry = fields[4]
+	default:
+		return fail(5, "unsupported symmetry %q", fields[4])
+	}
+	if h.pattern && !h.coordinate {
+		return fail(4, "pattern matrices must use coordinate layout")
+	}
+	return h, nil
+}
+
+// nextData skips comments and blank lines, returning the fields of the next
+// line that holds data.
+func (lr *lineReader) nextData() ([]string, error) {
+	for {
+		line, err := lr.next()
+		if err != nil {
+			return nil, err
+		}
+		if fields := strings.Fields(line); len(fields) > 0 && !strings.HasPrefix(fields[0], "%") {
+			return fields, nil
+		}
+	}
+}
+
+// ints parses every field as an integer.
+func (lr *lineReader) ints(fields []string) ([]int, error) {
+	out := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := parseInt(f)
+		if err != nil {
+			return nil, &ParseError{Line: lr.line, Col: i + 1, Err: err}
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+func decodeMarket(lr *lineReader) (Interface, error) {
+	first, err := lr.next()
+	if err == io.EOF {
+		return nil, &ParseError{Line: 1, Err: fmt.Errorf("%w: empty input", ErrHeader)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	h, err := parseMarketHeader(first)
+	if err != nil {
+		return nil, err
+	}
+
+	sizeFields, err := lr.nextData()
+	if err == io.EOF {
+		return nil, &ParseError{Line: lr.line + 1, Err: fmt.Errorf("%w: missing", ErrSizeLine)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	wantFields := 2
+	if h.coordinate {
+		wantFields = 3
+	}
+	if len(sizeFields) != wantFields {
+		return nil, &ParseError{Line: lr.line, Err: fmt.Errorf("%w: want %d values", ErrSizeLine, wantFields)}
+	}
+	size, err := lr.ints(sizeFields)
+	if err != nil {
+		return nil, err
+	}
+	for i, n := range size {
+		if n < 0 {
+			return nil, &ParseError{Line: lr.line, Col: i + 1, Err: fmt.Errorf("%w: negative size", ErrSizeLine)}
+		}
+	}
+	rows, cols := size[0], size[1]
+	// Nothing is allocated from the size line alone beyond what the entries
+	// that follow fill in, but the sizes still have to be ones the matrix can
+	// be indexed by.
+	elements := maxInt
+	if !h.coordinate {
+		// Every element of an array is stored, so there must be room in
+		// memory for all of them.
+		elements /= 8
+	}
+	if rows == maxInt || (rows > 0 && cols > elements/rows) {
+		return nil, &ParseError{Line: lr.line, Err: fmt.Errorf("%w: %d x %d is too big", ErrSizeLine, rows, cols)}
+	}
+	if h.coordinate && size[2] > rows*cols {
+		return nil, &ParseError{Line: lr.line, Col: 3, Err: fmt.Errorf("%w: %d entries in a %d x %d matrix", ErrSizeLine, size[2], rows, cols)}
+	}
+	if h.symmetry != "general" && rows != cols {
+		return nil, &ParseError{Line: lr.line, Err: fmt.Errorf("%w: %s matrix must be square", ErrSizeLine, h.symmetry)}
+	}
+
+	if h.coordinate {
+		return decodeCoordinate(lr, h, rows, cols, size[2])
+	}
+	return decodeArray(lr, h, rows, cols)
+}
+
+// maxInt is the largest value an int can hold.
+const maxInt = int(^uint(0) >> 1)
+
+// preallocate is the most entries decodeCoordinate makes room for before it has
+// read them, however many the size line promises.
+const preallocate = 1 << 16
+
+func decodeCoordinate(lr *lineReader, h marketHeader, rows, cols, count int) (*Sparse, error) {
+	wantFields := 3
+	if h.pattern {
+		wantFields = 2
+	}
+	entries := make([]Entry, 0, min(count, preallocate))
+	for n := 0; ; n++ {
+		fields, err := lr.nextData()
+		if err == io.EOF {
+			if n < count {
+				return nil, &ParseError{Line: lr.line, Err: fmt.Errorf("%w: got %d, want %d", ErrCount, n, count)}
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n == count {
+			return nil, &ParseError{Line: lr.line, Err: fmt.Errorf("%w: more than %d", ErrCount, count)}
+		}
+		if len(fields) != wantFields {
+			return nil, &ParseError{Line: lr.line, Err: fmt.Errorf("%w: want %d values on the line", ErrRaggedRow, wantFields)}
+		}
+		vals, err := lr.ints(fields)
+		if err != nil {
+			return nil, err
+		}
+
+		e := Entry{Row: vals[0] - 1, Col: vals[1] - 1, Val: 1}
+		if !h.pattern {
+			e.Val = vals[2]
+		}
+		switch {
+		case e.Row < 0 || e.Row >= rows:
+			return nil, &ParseError{Line: lr.line, Col: 1, Err: fmt.Errorf("%w: row %d", ErrIndex, vals[0])}
+		case e.Col < 0 || e.Col >= cols:
+			return nil, &ParseError{Line: lr.line, Col: 2, Err: fmt.Errorf("%w: column %d", ErrIndex, vals[1])}
+		}
+		entries = append(entries, e)
+		if e.Row != e.Col {
+			switch h.symmetry {
+			case "symmetric":
+				entries = append(entries, Entry{e.Col, e.Row, e.Val})
+			case "skew-symmetric":
+				entries = append(entries, Entry{e.Col, e.Row, -e.Val})
+			}
+		}
+	}
+	return NewSparseFromEntries(rows, cols, entries), nil
+}
+
+func decodeArray(lr *lineReader, h marketHeader, rows, cols int) (*Matrix, error) {
+	// Values are listed a column at a time; symmetric matrices list only the
+	// lower triangle, and skew-symmetric ones leave out the zero diagonal too.
+	// Each row is appended to as its values arrive, which is always in column
+	// order, so the matrix only grows as far as the input goes. A matrix with
+	// no elements at all comes back empty.
+	var m Matrix
+	row := func(i int) *[]int {
+		for len(m) <= i {
+			m = append(m, nil)
+		}
+		return &m[i]
+	}
+	want, got := 0, 0
+	for j := 0; j < cols; j++ {
+		from := 0
+		switch h.symmetry {
+		case "symmetric":
+			from = j
+		case "skew-symmetric":
+			from = j + 1
+			if j < rows {
+				r := row(j)
+				*r = append(*r, 0)
+			}
+		}
+		for i := from; i < rows; i++ {
+			want++
+			fields, err := lr.nextData()
+			if err == io.EOF {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			got++
+			if len(fields) != 1 {
+				return nil, &ParseError{Line: lr.line, Err: fmt.Errorf("%w: want one value on the line", ErrRaggedRow)}
+			}
+			vals, err := lr.ints(fields)
+			if err != nil {
+				return nil, err
+			}
+			r := row(i)
+			*r = append(*r, vals[0])
+			if i == j {
+				continue
+			}
+			switch h.symmetry {
+			case "symmetric":
+				r := row(j)
+				*r = append(*r, vals[0])
+			case "skew-symmetric":
+				r := row(j)
+				*r = append(*r, -vals[0])
+			}
+		}
+		if got < want {
+			// The input has run out, and want is counted up front from
+			// here on so the error can say how much was missing.
+			break
+		}
+	}
+	if got < want {
+		want = rows * cols
+		switch h.symmetry {
+		case "symmetric":
+			want = rows*rows/2 + (rows+1)/2
+		case "skew-symmetric":
+			want = rows*rows/2 - rows/2
+		}
+		return nil, &ParseError{Line: lr.line, Err: fmt.Errorf("%w: got %d, want %d", ErrCount, got, want)}
+	}
+	if _, err := lr.nextData(); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, &ParseError{Line: lr.line, Err: fmt.Errorf("%w: more than %d", ErrCount, want)}
+	}
+	return &m, nil
+}
+
+// Encode writes m to w in the given format, a row at a time. Text, CSV and TSV
+// output ends with a newline. Matrix Market output uses coordinate layout for a
+// *Sparse and array layout for anything else.
+func Encode(w io.Writer, m Interface, f Format) error {
+	bw := bufio.NewWriterSize(w, 64*1024)
+	var err error
+	switch f {
+	case Text:
+		err = encodeDelimited(bw, m, ' ')
+	case CSV:
+		err = encodeDelimited(bw, m, ',')
+	case TSV:
+		err = encodeDelimited(bw, m, '\t')
+	case MatrixMarket:
+		if s, ok := m.(*Sparse); ok {
+			err = encodeCoordinate(bw, s)
+		} else {
+			err = encodeArray(bw, m)
+		}
+	default:
+		return fmt.Errorf("matrix: %w %v", ErrFormat, f)
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func encodeDelimited(w *bufio.Writer, m Interface, sep byte) error {
+	rows, cols := m.Dims()
+	buf := make([]byte, 0, 64)
+	for i := 0; i < rows; i++ {
+		buf = buf[:0]
+		for j := 0; j < cols; j++ {
+			if j > 0 {
+				buf = append(buf, sep)
+			}
+			buf = strconv.AppendInt(buf, int64(m.At(i, j)), 10)
+		}
+		buf = append(buf, '\n')
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeCoordinate(w *bufio.Writer, s *Sparse) error {
+	fmt.Fprintf(w, "%%%%MatrixMarket matrix coordinate integer general\n%d %d %d\n", s.rows, s.cols, s.NonZero())
+	buf := make([]byte, 0, 64)
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			buf = strconv.AppendInt(buf[:0], int64(i+1), 10)
+			buf = append(buf, ' ')
+			buf = strconv.AppendInt(buf, int64(s.colIdx[k]+1), 10)
+			buf = append(buf, ' ')
+			buf = strconv.AppendInt(buf, int64(s.vals[k]), 10)
+			buf = append(buf, '\n')
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func encodeArray(w *bufio.Writer, m Interface) error {
+	rows, cols := m.Dims()
+	fmt.Fprintf(w, "%%%%MatrixMarket matrix array integer general\n%d %d\n", rows, cols)
+	buf := make([]byte, 0, 24)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			buf = strconv.AppendInt(buf[:0], int64(m.At(i, j)), 10)
+			buf = append(buf, '\n')
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package matrix
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDecodeDelimited(t *testing.T) {
+	want := Matrix{{1, 2, 3}, {-4, 5, 60}}
+	for _, test := range []struct {
+		f  Format
+		in string
+	}{
+		{Text, "1 2 3\n-4 5 60"},
+		{Text, "1  2 3\r\n -4 5\t60\n"},
+		{CSV, "1,2,3\n-4,5,60\n"},
+		{CSV, `1, "2" ,3` + "\r\n" + `"-4",5, 60`},
+		{TSV, "1\t2\t3\n-4\t5\t60\n"},
+	} {
+		m, err := Decode(strings.NewReader(test.in), test.f)
+		if err != nil {
+			t.Errorf("Decode(%q, %v) returned error: %v", test.in, test.f, err)
+			continue
+		}
+		if !reflect.DeepEqual(m.Rows(), [][]int(want)) {
+			t.Errorf("Decode(%q, %v) = %v, want %v", test.in, test.f, m.Rows(), want)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, test := range []struct {
+		f         Format
+		in        string
+		want      error
+		line, col int
+	}{
+		{Text, "", ErrEmptyRow, 1, 0},
+		{Text, "1 2\n\n3 4\n", ErrEmptyRow, 2, 0},
+		{Text, "1 2\n3 4 5\n", ErrRaggedRow, 2, 0},
+		{CSV, "1,2\n3,x\n", ErrNotInteger, 2, 2},
+		{CSV, "1,,2\n", ErrNotInteger, 1, 2},
+		{TSV, "1\t99999999999999999999\n", ErrOverflow, 1, 2},
+		{MatrixMarket, "", ErrHeader, 1, 0},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate real general\n", ErrHeader, 1, 4},
+		{MatrixMarket, "%%MatrixMarket vector coordinate integer general\n", ErrHeader, 1, 2},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer general\n% just a comment\n", ErrSizeLine, 3, 0},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer general\n2 2\n", ErrSizeLine, 2, 0},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer symmetric\n2 3 1\n", ErrSizeLine, 2, 0},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer general\n2 2 2\n1 1 5\n3 1 2\n", ErrIndex, 4, 1},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer general\n2 2 2\n1 1 5\n1 0 2\n", ErrIndex, 4, 2},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer general\n2 2 2\n1 1 5\n", ErrCount, 3, 0},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer general\n2 2 1\n1 1 5\n2 2 1\n", ErrCount, 4, 0},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer general\n2 2 1\n1 1 5.5\n", ErrNotInteger, 3, 3},
+		{MatrixMarket, "%%MatrixMarket matrix array integer general\n2 1\n7\n", ErrCount, 3, 0},
+		{MatrixMarket, "%%MatrixMarket matrix array integer general\n1 1\n7\n8\n", ErrCount, 4, 0},
+		{MatrixMarket, "%%MatrixMarket matrix array integer symmetric\n3 3\n1\n2\n", ErrCount, 4, 0},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer general\n9223372036854775807 1 0\n", ErrSizeLine, 2, 0},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer general\n4294967296 4294967296 0\n", ErrSizeLine, 2, 0},
+		{MatrixMarket, "%%MatrixMarket matrix coordinate integer general\n2 2 5\n", ErrSizeLine, 2, 3},
+		{MatrixMarket, "%%MatrixMarket matrix array integer general\n1 9223372036854775807\n", ErrSizeLine, 2, 0},
+		{MatrixMarket, "%%MatrixMarket matrix array integer general\n100000 100000\n1\n2\n", ErrCount, 4, 0},
+		{Format(42), "1", ErrFormat, 0, 0},
+	} {
+		_, err := Decode(strings.NewReader(test.in), test.f)
+		if !errors.Is(err, test.want) {
+			t.Errorf("Decode(%q, %v) error = %v, want %v", test.in, test.f, err, test.want)
+			continue
+		}
+		var perr *ParseError
+		if errors.As(err, &perr) && (perr.Line != test.line || perr.Col != test.col) {
+			t.Errorf("Decode(%q, %v) error at %d:%d, want %d:%d",
+				test.in, test.f, perr.Line, perr.Col, test.line, test.col)
+		}
+	}
+}
+
+func TestDecodeMatrixMarket(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		sparse bool
+		want   Matrix
+	}{
+		{
+			"%%MatrixMarket matrix coordinate integer general\n% comment\n\n3 4 3\n1 1 5\n3 4 -2\n2 3 7\n",
+			true, Matrix{{5, 0, 0, 0}, {0, 0, 7, 0}, {0, 0, 0, -2}},
+		},
+		{
+			"%%MatrixMarket matrix coordinate pattern symmetric\n3 3 2\n2 1\n3 3\n",
+			true, Matrix{{0, 1, 0}, {1, 0, 0}, {0, 0, 1}},
+		},
+		{
+			"%%MatrixMarket matrix coordinate integer skew-symmetric\n2 2 1\n2 1 4\n",
+			true, Matrix{{0, -4}, {4, 0}},
+		},
+		{
+			"%%MatrixMarket matrix array integer general\n2 3\n1\n4\n2\n5\n3\n6\n",
+			false, Matrix{{1, 2, 3}, {4, 5, 6}},
+		},
+		{
+			"%%matrixmarket MATRIX Array Integer Symmetric\n2 2\n1\n2\n3\n",
+			false, Matrix{{1, 2}, {2, 3}},
+		},
+		{
+			"%%MatrixMarket matrix array integer symmetric\n3 3\n1\n2\n3\n4\n5\n6\n",
+			false, Matrix{{1, 2, 3}, {2, 4, 5}, {3, 5, 6}},
+		},
+		{
+			"%%MatrixMarket matrix array integer skew-symmetric\n3 3\n1\n2\n3\n",
+			false, Matrix{{0, -1, -2}, {1, 0, -3}, {2, 3, 0}},
+		},
+		{
+			"%%MatrixMarket matrix array integer general\n3 1\n1\n2\n3\n",
+			false, Matrix{{1}, {2}, {3}},
+		},
+	} {
+		m, err := Decode(strings.NewReader(test.in), MatrixMarket)
+		if err != nil {
+			t.Errorf("Decode(%q) returned error: %v", test.in, err)
+			continue
+		}
+		if _, isSparse := m.(*Sparse); isSparse != test.sparse {
+			t.Errorf("Decode(%q) gave %T", test.in, m)
+		}
+		if !Equal(m, &test.want) {
+			t.Errorf("Decode(%q) = %v, want %v", test.in, m.Rows(), test.want)
+		}
+	}
+}
+
+func TestDecodeSizeLineAllocation(t *testing.T) {
+	// A size line on its own mustn't make Decode set aside room for the
+	// matrix it promises.
+	for _, in := range []string{
+		"%%MatrixMarket matrix array integer general\n100000 100000\n1\n",
+		"%%MatrixMarket matrix coordinate integer general\n100000 100000 1000000000\n1 1 1\n",
+	} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		if _, err := Decode(strings.NewReader(in), MatrixMarket); !errors.Is(err, ErrCount) {
+			t.Errorf("Decode(%q) error = %v, want ErrCount", in, err)
+		}
+		runtime.ReadMemStats(&after)
+		if n := after.TotalAlloc - before.TotalAlloc; n > 8<<20 {
+			t.Errorf("Decode(%q) allocated %d bytes", in, n)
+		}
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	m := randomSparse(rng, 7, 5, 3)
+	for _, f := range []Format{Text, CSV, TSV, MatrixMarket} {
+		for _, in := range []Interface{m, m.Sparse()} {
+			var buf bytes.Buffer
+			if err := Encode(&buf, in, f); err != nil {
+				t.Fatalf("Encode(%T, %v) returned error: %v", in, f, err)
+			}
+			out, err := Decode(&buf, f)
+			if err != nil {
+				t.Fatalf("Decode(Encode(%T, %v)) returned error: %v", in, f, err)
+			}
+			if !Equal(in, out) {
+				t.Errorf("Decode(Encode(%T, %v)) = %v, want %v", in, f, out.Rows(), in.Rows())
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	Encode(&buf, &Matrix{{1, -2}, {3, 4}}, CSV)
+	if got, want := buf.String(), "1,-2\n3,4\n"; got != want {
+		t.Errorf("Encode(CSV) = %q, want %q", got, want)
+	}
+	if err := Encode(&buf, m, Format(9)); !errors.Is(err, ErrFormat) {
+		t.Errorf("Encode(Format(9)) error = %v, want ErrFormat", err)
+	}
+}
+
+func TestDecodeLongLine(t *testing.T) {
+	// Rows longer than any read buffer must still come through whole.
+	row := strings.TrimSpace(strings.Repeat("7 ", 100000))
+	m, err := Decode(strings.NewReader(row+"\n"+row+"\n"), Text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, c := m.Dims(); r != 2 || c != 100000 {
+		t.Errorf("Decode of long rows gave %dx%d, want 2x100000", r, c)
+	}
+}
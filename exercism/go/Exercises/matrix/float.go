@@ -0,0 +1,237 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// FloatMatrix is a matrix of float64 values, for the arithmetic that doesn't
+// stay within the integers. It has the same methods as Matrix.
+type FloatMatrix [][]float64
+
+// epsilon scales the tolerance below which a pivot counts as zero.
+const epsilon = 1e-12
+
+// Dims returns the number of rows and columns in m.
+func (m *FloatMatrix) Dims() (rows, cols int) {
+	if len(*m) == 0 {
+		return 0, 0
+	}
+	return len(*m), len((*m)[0])
+}
+
+// fzeros makes a rows x cols matrix of zeroes.
+func fzeros(rows, cols int) FloatMatrix {
+	m := make(FloatMatrix, rows)
+	for i := range m {
+		m[i] = make([]float64, cols)
+	}
+	return m
+}
+
+// Rows outputs the rows of a matrix m
+func (m *FloatMatrix) Rows() [][]float64 {
+	row := make([][]float64, len(*m))
+	for i, j := range *m {
+		row[i] = append([]float64{}, j...)
+	}
+	return row
+}
+
+// Cols outputs the columns of a matrix m
+func (m *FloatMatrix) Cols() [][]float64 {
+	r, c := m.Dims()
+	col := fzeros(c, r)
+	for i, row := range *m {
+		for j, v := range row {
+			col[j][i] = v
+		}
+	}
+	return col
+}
+
+// Set modifies an element of m, reporting false if the row or column is
+// outside the matrix
+func (m *FloatMatrix) Set(row, col int, val float64) bool {
+	if row < 0 || row >= len(*m) || col < 0 || col >= len((*m)[row]) {
+		return false
+	}
+	(*m)[row][col] = val
+	return true
+}
+
+// Transpose returns a new matrix with the rows and columns of m swapped.
+func (m *FloatMatrix) Transpose() *FloatMatrix {
+	t := FloatMatrix(m.Cols())
+	return &t
+}
+
+// Add returns the element-wise sum of m and o, which must be the same shape.
+func (m *FloatMatrix) Add(o *FloatMatrix) (*FloatMatrix, error) {
+	r, c := m.Dims()
+	if or, oc := o.Dims(); r != or || c != oc {
+		return nil, &DimensionError{"Add", [2]int{r, c}, [2]int{or, oc}, ErrDimensionMismatch}
+	}
+	sum := fzeros(r, c)
+	for i := range sum {
+		for j := range sum[i] {
+			sum[i][j] = (*m)[i][j] + (*o)[i][j]
+		}
+	}
+	return &sum, nil
+}
+
+// Mul returns the matrix product m × o. m must have as many columns as o has
+// rows.
+func (m *FloatMatrix) Mul(o *FloatMatrix) (*FloatMatrix, error) {
+	r, n := m.Dims()
+	or, c := o.Dims()
+	if n != or {
+		return nil, &DimensionError{"Mul", [2]int{r, n}, [2]int{or, c}, ErrDimensionMismatch}
+	}
+	prod := fzeros(r, c)
+	for i := 0; i < r; i++ {
+		for k := 0; k < n; k++ {
+			a := (*m)[i][k]
+			for j, b := range (*o)[k] {
+				prod[i][j] += a * b
+			}
+		}
+	}
+	return &prod, nil
+}
+
+// ScalarMul returns m with every element multiplied by k.
+func (m *FloatMatrix) ScalarMul(k float64) *FloatMatrix {
+	r, c := m.Dims()
+	prod := fzeros(r, c)
+	for i := range prod {
+		for j := range prod[i] {
+			prod[i][j] = k * (*m)[i][j]
+		}
+	}
+	return &prod
+}
+
+// tolerance is how close to zero a pivot of m may get before m is treated as
+// singular, scaled to the size of its largest element.
+func (m *FloatMatrix) tolerance() float64 {
+	r, c := m.Dims()
+	largest := 0.0
+	for _, row := range *m {
+		for _, v := range row {
+			largest = math.Max(largest, math.Abs(v))
+		}
+	}
+	return epsilon * float64(r+c) * largest
+}
+
+// Determinant returns the determinant of a square matrix, using elimination
+// with partial pivoting.
+func (m *FloatMatrix) Determinant() (float64, error) {
+	r, c := m.Dims()
+	if r != c {
+		return 0, &DimensionError{Op: "Determinant", A: [2]int{r, c}, Err: ErrNotSquare}
+	}
+	a := FloatMatrix(m.Rows())
+	det := 1.0
+	for k := 0; k < r; k++ {
+		p := a.pivot(k, k)
+		if a[p][k] == 0 {
+			return 0, nil
+		}
+		if p != k {
+			a[k], a[p] = a[p], a[k]
+			det = -det
+		}
+		det *= a[k][k]
+		for i := k + 1; i < r; i++ {
+			f := a[i][k] / a[k][k]
+			for j := k; j < r; j++ {
+				a[i][j] -= f * a[k][j]
+			}
+		}
+	}
+	return det, nil
+}
+
+// pivot returns the row at or below from with the largest value in column col.
+func (m FloatMatrix) pivot(from, col int) int {
+	best := from
+	for i := from + 1; i < len(m); i++ {
+		if math.Abs(m[i][col]) > math.Abs(m[best][col]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// Rank returns the number of linearly independent rows in m, treating values
+// that rounding has left very close to zero as zero.
+func (m *FloatMatrix) Rank() int {
+	rows, cols := m.Dims()
+	a := FloatMatrix(m.Rows())
+	tol := m.tolerance()
+	rank := 0
+	for c := 0; c < cols && rank < rows; c++ {
+		p := a.pivot(rank, c)
+		if math.Abs(a[p][c]) <= tol {
+			continue
+		}
+		a[rank], a[p] = a[p], a[rank]
+		for i := rank + 1; i < rows; i++ {
+			f := a[i][c] / a[rank][c]
+			for j := c; j < cols; j++ {
+				a[i][j] -= f * a[rank][j]
+			}
+		}
+		rank++
+	}
+	return rank
+}
+
+// Inverse returns the inverse of a square matrix, using Gauss-Jordan
+// elimination with partial pivoting.
+func (m *FloatMatrix) Inverse() (*FloatMatrix, error) {
+	if r, c := m.Dims(); r != c {
+		return nil, &DimensionError{Op: "Inverse", A: [2]int{r, c}, Err: ErrNotSquare}
+	}
+	return m.inverse(m.tolerance())
+}
+
+// inverse does the work for Inverse, treating any pivot no bigger than tol as
+// zero.
+func (m *FloatMatrix) inverse(tol float64) (*FloatMatrix, error) {
+	n, _ := m.Dims()
+	a := FloatMatrix(m.Rows())
+	inv := fzeros(n, n)
+	for i := range inv {
+		inv[i][i] = 1
+	}
+
+	for k := 0; k < n; k++ {
+		p := a.pivot(k, k)
+		if math.Abs(a[p][k]) <= tol {
+			return nil, fmt.Errorf("matrix: Inverse: %w", ErrSingular)
+		}
+		a[k], a[p] = a[p], a[k]
+		inv[k], inv[p] = inv[p], inv[k]
+
+		f := a[k][k]
+		for j := 0; j < n; j++ {
+			a[k][j] /= f
+			inv[k][j] /= f
+		}
+		for i := 0; i < n; i++ {
+			if i == k || a[i][k] == 0 {
+				continue
+			}
+			f := a[i][k]
+			for j := 0; j < n; j++ {
+				a[i][j] -= f * a[k][j]
+				inv[i][j] -= f * inv[k][j]
+			}
+		}
+	}
+	return &inv, nil
+}
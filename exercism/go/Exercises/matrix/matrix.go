@@ -1,22 +1,118 @@
-/* Package matrix creates an arbitrary matrix from a given string and parses
-   that matrix to find a given element, and can change the value of it.
+/*
+Package matrix creates an arbitrary matrix from a given string and parses
+that matrix to find a given element, and can change the value of it. Matrices
+can also be added, multiplied, transposed and inverted.
 */
 package matrix
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // Define the Matrix type here.
 
 type Matrix [][]int
 
-// New converts a string into a matrix
+// These are the ways New can fail. The error it returns is a *ParseError
+// wrapping one of them, so they can be checked with errors.Is.
+var (
+	ErrEmptyRow   = errors.New("row is empty")
+	ErrRaggedRow  = errors.New("row length differs from the first row")
+	ErrNotInteger = errors.New("value is not an integer")
+	ErrOverflow   = errors.New("value overflows int")
+)
+
+// ParseError records where in the input a matrix could not be read. Line and
+// Col count from 1; Col counts values along the line, and is 0 when the problem
+// is with the line as a whole.
+type ParseError struct {
+	Line, Col int
+	Err       error
+}
+
+func (e *ParseError) Error() string {
+	if e.Col == 0 {
+		return fmt.Sprintf("matrix: line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("matrix: line %d, column %d: %v", e.Line, e.Col, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// New converts a string into a matrix. Rows are separated by newlines and the
+// values in a row by spaces; every row must have as many values as the first.
 func New(s string) (*Matrix, error) {
+	lines := strings.Split(s, "\n")
+	m := make(Matrix, len(lines))
+	for i, line := range lines {
+		width := -1
+		if i > 0 {
+			width = len(m[0])
+		}
+		row, err := parseRow(i+1, strings.Fields(line), width)
+		if err != nil {
+			return nil, err
+		}
+		m[i] = row
+	}
+	return &m, nil
+}
+
+// parseRow reads the values on one line of input. Unless width is negative the
+// row must have exactly that many values.
+func parseRow(line int, fields []string, width int) ([]int, error) {
+	switch {
+	case len(fields) == 0:
+		return nil, &ParseError{Line: line, Err: ErrEmptyRow}
+	case width >= 0 && len(fields) != width:
+		return nil, &ParseError{Line: line, Err: ErrRaggedRow}
+	}
+
+	row := make([]int, len(fields))
+	for j, field := range fields {
+		n, err := parseInt(field)
+		if err != nil {
+			return nil, &ParseError{Line: line, Col: j + 1, Err: err}
+		}
+		row[j] = n
+	}
+	return row, nil
+}
+
+// parseInt reads a single value, telling apart values that aren't integers
+// from integers too big to store.
+func parseInt(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	var numErr *strconv.NumError
+	switch {
+	case err == nil:
+		return n, nil
+	case errors.As(err, &numErr) && numErr.Err == strconv.ErrRange:
+		return 0, fmt.Errorf("%w: %q", ErrOverflow, s)
+	}
+	return 0, fmt.Errorf("%w: %q", ErrNotInteger, s)
 }
 
 // Cols and Rows must return the results without affecting the matrix.
 
-//Cols outputs the columns of a matrix m
+// Cols outputs the columns of a matrix m
 func (m *Matrix) Cols() [][]int {
+	if len(*m) == 0 {
+		return [][]int{}
+	}
+	col := make([][]int, len((*m)[0]))
+	for j := range col {
+		col[j] = make([]int, len(*m))
+		for i, row := range *m {
+			col[j][i] = row[j]
+		}
+	}
+	return col
 }
 
 // Rows outputs the rows of a matrix m
@@ -28,6 +124,12 @@ func (m *Matrix) Rows() [][]int {
 	return row
 }
 
-// Set modifies the elment if an mxn matrix
+// Set modifies the elment if an mxn matrix, reporting false if the row or
+// column is outside the matrix
 func (m *Matrix) Set(row, col, val int) bool {
+	if row < 0 || row >= len(*m) || col < 0 || col >= len((*m)[row]) {
+		return false
+	}
+	(*m)[row][col] = val
+	return true
 }
@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -252,6 +253,36 @@ func TestSet(t *testing.T) {
 	}
 }
 
+func TestNewErrors(t *testing.T) {
+	for _, test := range []struct {
+		in        string
+		want      error
+		line, col int
+	}{
+		{"9223372036854775808", ErrOverflow, 1, 1},
+		{"1 2\n3 -9223372036854775809", ErrOverflow, 2, 2},
+		{"1 2\n10 20 30", ErrRaggedRow, 2, 0},
+		{"1 2 3\n10 20", ErrRaggedRow, 2, 0},
+		{"\n3 4\n5 6", ErrEmptyRow, 1, 0},
+		{"1 2\n  \n5 6", ErrEmptyRow, 2, 0},
+		{"1 2\n3 4\n", ErrEmptyRow, 3, 0},
+		{"", ErrEmptyRow, 1, 0},
+		{"2.7", ErrNotInteger, 1, 1},
+		{"1 cat", ErrNotInteger, 1, 2},
+	} {
+		_, err := New(test.in)
+		var perr *ParseError
+		if !errors.Is(err, test.want) || !errors.As(err, &perr) {
+			t.Errorf("New(%q) error = %v, want %v", test.in, err, test.want)
+			continue
+		}
+		if perr.Line != test.line || perr.Col != test.col {
+			t.Errorf("New(%q) error at %d:%d, want %d:%d",
+				test.in, perr.Line, perr.Col, test.line, test.col)
+		}
+	}
+}
+
 func BenchmarkNew(b *testing.B) {
 	if testing.Short() {
 		b.Skip("skipping benchmark in short mode.")
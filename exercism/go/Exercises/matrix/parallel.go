@@ -0,0 +1,90 @@
+package matrix
+
+import (
+	"runtime"
+	"sync"
+)
+
+// blockSize is the edge length of the square tiles ParallelMul works on. Three
+// 64x64 tiles of ints fit comfortably in a typical L2 cache.
+const blockSize = 64
+
+// tile is one blockSize x blockSize piece of the product, named by the row and
+// column it starts at.
+type tile struct {
+	row, col int
+}
+
+// ParallelMul returns the matrix product m × o, like Mul, but splits the work
+// across a pool of goroutines. The product is cut into square tiles, and each
+// worker takes tiles off a queue and fills them in a block at a time, so the
+// pieces of m and o it is reading stay in cache. A workers count below one
+// means one per CPU.
+//
+// Every tile of the product is written by exactly one worker, and integer
+// addition doesn't care about order, so the result is always identical to Mul.
+func (m *Matrix) ParallelMul(o *Matrix, workers int) (*Matrix, error) {
+	r, n := m.Dims()
+	or, c := o.Dims()
+	if n != or {
+		return nil, &DimensionError{"ParallelMul", [2]int{r, n}, [2]int{or, c}, ErrDimensionMismatch}
+	}
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	prod := zeros(r, c)
+
+	// Fill a buffered channel with every tile up front, so the workers can
+	// simply drain it and stop once it's empty.
+	tiles := make(chan tile, ((r+blockSize-1)/blockSize)*((c+blockSize-1)/blockSize))
+	for i := 0; i < r; i += blockSize {
+		for j := 0; j < c; j += blockSize {
+			tiles <- tile{i, j}
+		}
+	}
+	close(tiles)
+
+	// Create a WaitGroup, so we know when every worker has run out of tiles.
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer waitGroup.Done()
+			for t := range tiles {
+				mulTile(*m, *o, prod, t, n)
+			}
+		}()
+	}
+	waitGroup.Wait()
+	return &prod, nil
+}
+
+// mulTile fills one tile of prod = a × b, where a has n columns. It walks the
+// shared dimension a block at a time as well, so each pass only touches one
+// block of a and one block of b.
+func mulTile(a, b, prod Matrix, t tile, n int) {
+	rowEnd := min(t.row+blockSize, len(prod))
+	colEnd := min(t.col+blockSize, len(prod[0]))
+	for kk := 0; kk < n; kk += blockSize {
+		kEnd := min(kk+blockSize, n)
+		for i := t.row; i < rowEnd; i++ {
+			out := prod[i][t.col:colEnd]
+			for k := kk; k < kEnd; k++ {
+				x := a[i][k]
+				if x == 0 {
+					continue
+				}
+				for j, y := range b[k][t.col:colEnd] {
+					out[j] += x * y
+				}
+			}
+		}
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,62 @@
+package matrix
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestParallelMul(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for _, dims := range [][3]int{
+		{1, 1, 1},
+		{3, 5, 2},
+		{64, 64, 64},
+		{65, 63, 130},
+		{200, 150, 97},
+	} {
+		a := randomSparse(rng, dims[0], dims[1], 1)
+		b := randomSparse(rng, dims[1], dims[2], 1)
+		want, _ := a.Mul(b)
+		for _, workers := range []int{0, 1, 3, 16} {
+			got, err := a.ParallelMul(b, workers)
+			if err != nil {
+				t.Fatalf("ParallelMul(%v, %d) returned error: %v", dims, workers, err)
+			}
+			if !reflect.DeepEqual(*got, *want) {
+				t.Fatalf("ParallelMul(%v, %d) differs from Mul", dims, workers)
+			}
+		}
+	}
+
+	a := mustNew(t, "1 2 3")
+	if _, err := a.ParallelMul(a, 2); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("ParallelMul() of 1x3 and 1x3 error = %v, want ErrDimensionMismatch", err)
+	}
+}
+
+func benchmarkMul(b *testing.B, mul func(x, y *Matrix)) {
+	if testing.Short() {
+		b.Skip("skipping benchmark in short mode.")
+	}
+	rng := rand.New(rand.NewSource(4))
+	x := randomSparse(rng, 512, 512, 1)
+	y := randomSparse(rng, 512, 512, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mul(x, y)
+	}
+}
+
+func BenchmarkMul512(b *testing.B) {
+	benchmarkMul(b, func(x, y *Matrix) { x.Mul(y) })
+}
+
+func BenchmarkParallelMul512(b *testing.B) {
+	benchmarkMul(b, func(x, y *Matrix) { x.ParallelMul(y, 0) })
+}
+
+func BenchmarkParallelMul512OneWorker(b *testing.B) {
+	benchmarkMul(b, func(x, y *Matrix) { x.ParallelMul(y, 1) })
+}
@@ -0,0 +1,416 @@
+package matrix
+
+import "sort"
+
+// Interface is what both Matrix and Sparse provide, for callers that don't
+// care how a matrix is stored. The arithmetic that works on either kind is
+// provided by the package-level Add, Mul, Transpose and ScalarMul.
+type Interface interface {
+	Dims() (rows, cols int)
+	At(row, col int) int
+	Set(row, col, val int) bool
+	Rows() [][]int
+	Cols() [][]int
+}
+
+var (
+	_ Interface = (*Matrix)(nil)
+	_ Interface = (*Sparse)(nil)
+)
+
+// At returns the element at the given row and column, which must be inside the
+// matrix.
+func (m *Matrix) At(row, col int) int {
+	return (*m)[row][col]
+}
+
+// Sparse is a matrix that only stores its non-zero elements, in compressed
+// sparse row (CSR) form. It suits large matrices that are mostly zeroes: memory
+// grows with the number of non-zero elements, and so does the cost of Mul.
+//
+// Reading an element is a binary search along its row. Set has to shift every
+// element stored after the one it changes, so build large matrices with
+// NewSparseFromEntries, or convert a Matrix, rather than Set one element at a
+// time.
+type Sparse struct {
+	rows, cols int
+	rowStart   []int // row i is stored in colIdx/vals[rowStart[i]:rowStart[i+1]]
+	colIdx     []int // column of each stored element, ascending within a row
+	vals       []int // value of each stored element, never zero
+}
+
+// Entry is one element of a sparse matrix.
+type Entry struct {
+	Row, Col, Val int
+}
+
+// NewSparse returns an all-zero rows x cols sparse matrix.
+func NewSparse(rows, cols int) *Sparse {
+	return &Sparse{rows: rows, cols: cols, rowStart: make([]int, rows+1)}
+}
+
+// NewSparseFromEntries builds a rows x cols sparse matrix from its non-zero
+// elements, given in any order. Repeated positions are added together, and
+// entries outside the matrix are ignored.
+func NewSparseFromEntries(rows, cols int, entries []Entry) *Sparse {
+	sorted := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if e.Row >= 0 && e.Row < rows && e.Col >= 0 && e.Col < cols {
+			sorted = append(sorted, e)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Row != sorted[j].Row {
+			return sorted[i].Row < sorted[j].Row
+		}
+		return sorted[i].Col < sorted[j].Col
+	})
+
+	s := NewSparse(rows, cols)
+	for i := 0; i < len(sorted); {
+		e := sorted[i]
+		for i++; i < len(sorted) && sorted[i].Row == e.Row && sorted[i].Col == e.Col; i++ {
+			e.Val += sorted[i].Val
+		}
+		if e.Val != 0 {
+			s.colIdx = append(s.colIdx, e.Col)
+			s.vals = append(s.vals, e.Val)
+			s.rowStart[e.Row+1]++
+		}
+	}
+	for i := 0; i < rows; i++ {
+		s.rowStart[i+1] += s.rowStart[i]
+	}
+	return s
+}
+
+// Sparse returns a copy of m in sparse form.
+func (m *Matrix) Sparse() *Sparse {
+	r, c := m.Dims()
+	s := NewSparse(r, c)
+	for i, row := range *m {
+		for j, v := range row {
+			if v != 0 {
+				s.colIdx = append(s.colIdx, j)
+				s.vals = append(s.vals, v)
+			}
+		}
+		s.rowStart[i+1] = len(s.vals)
+	}
+	return s
+}
+
+// Dense returns a copy of s as an ordinary Matrix.
+func (s *Sparse) Dense() *Matrix {
+	m := zeros(s.rows, s.cols)
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			m[i][s.colIdx[k]] = s.vals[k]
+		}
+	}
+	return &m
+}
+
+// Dims returns the number of rows and columns in s.
+func (s *Sparse) Dims() (rows, cols int) {
+	return s.rows, s.cols
+}
+
+// NonZero returns how many elements of s are stored.
+func (s *Sparse) NonZero() int {
+	return len(s.vals)
+}
+
+// Entries returns the non-zero elements of s, row by row.
+func (s *Sparse) Entries() []Entry {
+	entries := make([]Entry, 0, len(s.vals))
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			entries = append(entries, Entry{i, s.colIdx[k], s.vals[k]})
+		}
+	}
+	return entries
+}
+
+// find returns where the element at row, col is or would be stored, and
+// whether it is stored.
+func (s *Sparse) find(row, col int) (int, bool) {
+	lo, hi := s.rowStart[row], s.rowStart[row+1]
+	k := lo + sort.SearchInts(s.colIdx[lo:hi], col)
+	return k, k < hi && s.colIdx[k] == col
+}
+
+// At returns the element at the given row and column, which must be inside the
+// matrix.
+func (s *Sparse) At(row, col int) int {
+	if row < 0 || row >= s.rows || col < 0 || col >= s.cols {
+		panic("matrix: Sparse.At out of range")
+	}
+	if k, ok := s.find(row, col); ok {
+		return s.vals[k]
+	}
+	return 0
+}
+
+// Set modifies an element of s, reporting false if the row or column is
+// outside the matrix. Setting an element to zero stops storing it.
+func (s *Sparse) Set(row, col, val int) bool {
+	if row < 0 || row >= s.rows || col < 0 || col >= s.cols {
+		return false
+	}
+	k, ok := s.find(row, col)
+	switch {
+	case ok && val != 0:
+		s.vals[k] = val
+		return true
+	case ok:
+		s.colIdx = append(s.colIdx[:k], s.colIdx[k+1:]...)
+		s.vals = append(s.vals[:k], s.vals[k+1:]...)
+		for i := row + 1; i <= s.rows; i++ {
+			s.rowStart[i]--
+		}
+	case val != 0:
+		s.colIdx = append(s.colIdx, 0)
+		copy(s.colIdx[k+1:], s.colIdx[k:])
+		s.colIdx[k] = col
+		s.vals = append(s.vals, 0)
+		copy(s.vals[k+1:], s.vals[k:])
+		s.vals[k] = val
+		for i := row + 1; i <= s.rows; i++ {
+			s.rowStart[i]++
+		}
+	}
+	return true
+}
+
+// Rows outputs the rows of s, zeroes included.
+func (s *Sparse) Rows() [][]int {
+	return s.Dense().Rows()
+}
+
+// Cols outputs the columns of s, zeroes included.
+func (s *Sparse) Cols() [][]int {
+	return s.Transpose().Rows()
+}
+
+// Transpose returns a new sparse matrix with the rows and columns of s swapped.
+func (s *Sparse) Transpose() *Sparse {
+	t := NewSparse(s.cols, s.rows)
+	// Count the elements in each column to find where each row of t starts.
+	for _, c := range s.colIdx {
+		t.rowStart[c+1]++
+	}
+	for i := 0; i < t.rows; i++ {
+		t.rowStart[i+1] += t.rowStart[i]
+	}
+
+	t.colIdx = make([]int, len(s.vals))
+	t.vals = make([]int, len(s.vals))
+	next := append([]int{}, t.rowStart[:t.rows]...)
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			c := s.colIdx[k]
+			t.colIdx[next[c]] = i
+			t.vals[next[c]] = s.vals[k]
+			next[c]++
+		}
+	}
+	return t
+}
+
+// Add returns the element-wise sum of s and o, which must be the same shape.
+func (s *Sparse) Add(o *Sparse) (*Sparse, error) {
+	if s.rows != o.rows || s.cols != o.cols {
+		return nil, &DimensionError{"Add", [2]int{s.rows, s.cols}, [2]int{o.rows, o.cols}, ErrDimensionMismatch}
+	}
+	sum := NewSparse(s.rows, s.cols)
+	for i := 0; i < s.rows; i++ {
+		// Merge the two rows, which are both sorted by column.
+		a, aEnd := s.rowStart[i], s.rowStart[i+1]
+		b, bEnd := o.rowStart[i], o.rowStart[i+1]
+		for a < aEnd || b < bEnd {
+			var col, val int
+			switch {
+			case b == bEnd || (a < aEnd && s.colIdx[a] < o.colIdx[b]):
+				col, val = s.colIdx[a], s.vals[a]
+				a++
+			case a == aEnd || o.colIdx[b] < s.colIdx[a]:
+				col, val = o.colIdx[b], o.vals[b]
+				b++
+			default:
+				col, val = s.colIdx[a], s.vals[a]+o.vals[b]
+				a++
+				b++
+			}
+			if val != 0 {
+				sum.colIdx = append(sum.colIdx, col)
+				sum.vals = append(sum.vals, val)
+			}
+		}
+		sum.rowStart[i+1] = len(sum.vals)
+	}
+	return sum, nil
+}
+
+// Mul returns the matrix product s × o. s must have as many columns as o has
+// rows. Only pairs of non-zero elements are ever multiplied.
+func (s *Sparse) Mul(o *Sparse) (*Sparse, error) {
+	if s.cols != o.rows {
+		return nil, &DimensionError{"Mul", [2]int{s.rows, s.cols}, [2]int{o.rows, o.cols}, ErrDimensionMismatch}
+	}
+	prod := NewSparse(s.rows, o.cols)
+	// acc gathers one row of the product at a time; touched lists the columns
+	// of acc in use so they can be collected and cleared without a full scan.
+	acc := make([]int, o.cols)
+	used := make([]bool, o.cols)
+	var touched []int
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			a := s.vals[k]
+			r := s.colIdx[k]
+			for kk := o.rowStart[r]; kk < o.rowStart[r+1]; kk++ {
+				c := o.colIdx[kk]
+				if !used[c] {
+					used[c] = true
+					touched = append(touched, c)
+				}
+				acc[c] += a * o.vals[kk]
+			}
+		}
+		sort.Ints(touched)
+		for _, c := range touched {
+			if acc[c] != 0 {
+				prod.colIdx = append(prod.colIdx, c)
+				prod.vals = append(prod.vals, acc[c])
+			}
+			acc[c], used[c] = 0, false
+		}
+		touched = touched[:0]
+		prod.rowStart[i+1] = len(prod.vals)
+	}
+	return prod, nil
+}
+
+// Determinant returns the determinant of a square sparse matrix. It is worked
+// out on a dense copy, as Matrix.Determinant does, so it takes as long and as
+// much memory as it would for a Matrix of the same size.
+func (s *Sparse) Determinant() (int, error) {
+	return s.Dense().Determinant()
+}
+
+// Rank returns the number of linearly independent rows in s, worked out on a
+// dense copy as Matrix.Rank does.
+func (s *Sparse) Rank() int {
+	return s.Dense().Rank()
+}
+
+// Inverse returns the inverse of a square sparse matrix, worked out on a dense
+// copy as Matrix.Inverse does. The inverse of a sparse matrix is usually dense,
+// so it comes back as a FloatMatrix.
+func (s *Sparse) Inverse() (*FloatMatrix, error) {
+	return s.Dense().Inverse()
+}
+
+// ScalarMul returns s with every element multiplied by k.
+func (s *Sparse) ScalarMul(k int) *Sparse {
+	if k == 0 {
+		return NewSparse(s.rows, s.cols)
+	}
+	prod := &Sparse{
+		rows:     s.rows,
+		cols:     s.cols,
+		rowStart: append([]int{}, s.rowStart...),
+		colIdx:   append([]int{}, s.colIdx...),
+		vals:     make([]int, len(s.vals)),
+	}
+	for i, v := range s.vals {
+		prod.vals[i] = k * v
+	}
+	return prod
+}
+
+// Equal reports whether a and b have the same shape and elements, however they
+// are stored.
+func Equal(a, b Interface) bool {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		return false
+	}
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			if a.At(i, j) != b.At(i, j) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// dense returns a as a Matrix, copying it unless it already is one.
+func dense(a Interface) *Matrix {
+	switch a := a.(type) {
+	case *Matrix:
+		return a
+	case *Sparse:
+		return a.Dense()
+	}
+	m := Matrix(a.Rows())
+	return &m
+}
+
+// Add returns the element-wise sum of a and b, which must be the same shape.
+// The sum is a *Sparse if both are, and a *Matrix otherwise.
+func Add(a, b Interface) (Interface, error) {
+	if as, ok := a.(*Sparse); ok {
+		if bs, ok := b.(*Sparse); ok {
+			sum, err := as.Add(bs)
+			if err != nil {
+				return nil, err
+			}
+			return sum, nil
+		}
+	}
+	sum, err := dense(a).Add(dense(b))
+	if err != nil {
+		return nil, err
+	}
+	return sum, nil
+}
+
+// Mul returns the matrix product a × b. a must have as many columns as b has
+// rows. The product is a *Sparse if both are, and a *Matrix otherwise.
+func Mul(a, b Interface) (Interface, error) {
+	if as, ok := a.(*Sparse); ok {
+		if bs, ok := b.(*Sparse); ok {
+			prod, err := as.Mul(bs)
+			if err != nil {
+				return nil, err
+			}
+			return prod, nil
+		}
+	}
+	prod, err := dense(a).Mul(dense(b))
+	if err != nil {
+		return nil, err
+	}
+	return prod, nil
+}
+
+// Transpose returns a new matrix with the rows and columns of a swapped, stored
+// the same way as a if it is a *Sparse, and as a *Matrix otherwise.
+func Transpose(a Interface) Interface {
+	if s, ok := a.(*Sparse); ok {
+		return s.Transpose()
+	}
+	return dense(a).Transpose()
+}
+
+// ScalarMul returns a with every element multiplied by k, stored the same way
+// as a if it is a *Sparse, and as a *Matrix otherwise.
+func ScalarMul(a Interface, k int) Interface {
+	if s, ok := a.(*Sparse); ok {
+		return s.ScalarMul(k)
+	}
+	return dense(a).ScalarMul(k)
+}
@@ -0,0 +1,209 @@
+package matrix
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// randomSparse makes a rows x cols matrix where roughly one element in every
+// `every` is non-zero.
+func randomSparse(rng *rand.Rand, rows, cols, every int) *Matrix {
+	m := zeros(rows, cols)
+	for i := range m {
+		for j := range m[i] {
+			if rng.Intn(every) == 0 {
+				m[i][j] = rng.Intn(19) - 9
+			}
+		}
+	}
+	return &m
+}
+
+func TestSparseRoundTrip(t *testing.T) {
+	for _, test := range tests {
+		if !test.ok {
+			continue
+		}
+		m := mustNew(t, test.in)
+		s := m.Sparse()
+		if !reflect.DeepEqual(s.Rows(), test.rows) || !reflect.DeepEqual(s.Cols(), test.cols) {
+			t.Errorf("New(%q).Sparse() rows %v, cols %v, want %v, %v",
+				test.in, s.Rows(), s.Cols(), test.rows, test.cols)
+		}
+		if !reflect.DeepEqual(*s.Dense(), *m) || !Equal(s, m) {
+			t.Errorf("New(%q).Sparse().Dense() = %v, want %v", test.in, *s.Dense(), *m)
+		}
+	}
+}
+
+func TestSparseSet(t *testing.T) {
+	var s Interface = NewSparse(3, 4)
+	var d Interface = &Matrix{make([]int, 4), make([]int, 4), make([]int, 4)}
+	for _, e := range []Entry{
+		{1, 2, 5}, {1, 0, 3}, {1, 3, 7}, {0, 0, 1}, {2, 3, 4},
+		{1, 2, 0}, {0, 0, 0}, {0, 0, -2}, {1, 2, 6}, {2, 3, 0},
+	} {
+		if !s.Set(e.Row, e.Col, e.Val) || !d.Set(e.Row, e.Col, e.Val) {
+			t.Fatalf("Set(%d, %d, %d) failed", e.Row, e.Col, e.Val)
+		}
+		if !Equal(s, d) {
+			t.Fatalf("after Set(%d, %d, %d): sparse %v, dense %v", e.Row, e.Col, e.Val, s.Rows(), d.Rows())
+		}
+	}
+	if n := s.(*Sparse).NonZero(); n != 4 {
+		t.Errorf("NonZero() = %d, want 4", n)
+	}
+	for _, pos := range [][2]int{{-1, 0}, {0, -1}, {3, 0}, {0, 4}} {
+		if s.Set(pos[0], pos[1], 1) {
+			t.Errorf("Set(%d, %d, 1) = true, want false", pos[0], pos[1])
+		}
+	}
+}
+
+func TestSparseFromEntries(t *testing.T) {
+	s := NewSparseFromEntries(2, 3, []Entry{
+		{1, 2, 4}, {0, 1, 2}, {1, 2, -1}, {0, 0, 5}, {0, 0, -5}, {5, 5, 1},
+	})
+	if want := (Matrix{{0, 2, 0}, {0, 0, 3}}); !reflect.DeepEqual(*s.Dense(), want) {
+		t.Errorf("NewSparseFromEntries() = %v, want %v", *s.Dense(), want)
+	}
+	if want := []Entry{{0, 1, 2}, {1, 2, 3}}; !reflect.DeepEqual(s.Entries(), want) {
+		t.Errorf("Entries() = %v, want %v", s.Entries(), want)
+	}
+}
+
+func TestSparseArithmetic(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		r, n, c := 1+rng.Intn(12), 1+rng.Intn(12), 1+rng.Intn(12)
+		a, b, b2 := randomSparse(rng, r, n, 4), randomSparse(rng, n, c, 4), randomSparse(rng, r, n, 3)
+
+		prod, _ := a.Mul(b)
+		sprod, err := a.Sparse().Mul(b.Sparse())
+		if err != nil || !Equal(sprod, prod) {
+			t.Fatalf("Sparse Mul = %v, %v, want %v", sprod.Rows(), err, *prod)
+		}
+		sum, _ := a.Add(b2)
+		ssum, err := a.Sparse().Add(b2.Sparse())
+		if err != nil || !Equal(ssum, sum) {
+			t.Fatalf("Sparse Add = %v, %v, want %v", ssum.Rows(), err, *sum)
+		}
+		if !Equal(a.Sparse().Transpose(), a.Transpose()) {
+			t.Fatalf("Sparse Transpose = %v, want %v", a.Sparse().Transpose().Rows(), *a.Transpose())
+		}
+		if !Equal(a.Sparse().ScalarMul(-3), a.ScalarMul(-3)) {
+			t.Fatalf("Sparse ScalarMul differs from dense")
+		}
+	}
+
+	a := NewSparse(2, 3)
+	if _, err := a.Mul(a); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Mul() of 2x3 and 2x3 error = %v, want ErrDimensionMismatch", err)
+	}
+	if _, err := a.Add(a.Transpose()); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Add() of 2x3 and 3x2 error = %v, want ErrDimensionMismatch", err)
+	}
+	if n := a.ScalarMul(0).NonZero(); n != 0 {
+		t.Errorf("ScalarMul(0) stored %d elements", n)
+	}
+}
+
+func TestInterfaceArithmetic(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	a, b := randomSparse(rng, 5, 4, 3), randomSparse(rng, 4, 6, 3)
+	a2 := randomSparse(rng, 5, 4, 3)
+	for _, tt := range []struct {
+		name   string
+		a, b   Interface
+		sparse bool
+	}{
+		{"dense", a, b, false},
+		{"sparse", a.Sparse(), b.Sparse(), true},
+		{"mixed", a.Sparse(), b, false},
+	} {
+		_, aSparse := tt.a.(*Sparse)
+		check := func(op string, got Interface, want *Matrix, sparse bool) {
+			t.Helper()
+			if _, isSparse := got.(*Sparse); isSparse != sparse {
+				t.Errorf("%s %s returned %T", tt.name, op, got)
+			}
+			if !Equal(got, want) {
+				t.Errorf("%s %s = %v, want %v", tt.name, op, got.Rows(), *want)
+			}
+		}
+		prod, err := Mul(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("%s Mul: %v", tt.name, err)
+		}
+		want, _ := a.Mul(b)
+		check("Mul", prod, want, tt.sparse)
+
+		var other Interface = a2
+		if tt.sparse {
+			other = a2.Sparse()
+		}
+		sum, err := Add(tt.a, other)
+		if err != nil {
+			t.Fatalf("%s Add: %v", tt.name, err)
+		}
+		want, _ = a.Add(a2)
+		check("Add", sum, want, tt.sparse)
+		check("Transpose", Transpose(tt.a), a.Transpose(), aSparse)
+		check("ScalarMul", ScalarMul(tt.a, 4), a.ScalarMul(4), aSparse)
+
+		if got, err := Mul(tt.a, tt.a); got != nil || !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("%s Mul of 5x4 and 5x4 = %v, %v, want nil, ErrDimensionMismatch", tt.name, got, err)
+		}
+		if got, err := Add(tt.a, tt.b); got != nil || !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("%s Add of 5x4 and 4x6 = %v, %v, want nil, ErrDimensionMismatch", tt.name, got, err)
+		}
+	}
+}
+
+func TestSparseSquare(t *testing.T) {
+	m := mustNew(t, "2 0 0\n0 0 3\n0 1 0")
+	s := m.Sparse()
+	if det, err := s.Determinant(); err != nil || det != -6 {
+		t.Errorf("Determinant() = %d, %v, want -6", det, err)
+	}
+	if r := s.Rank(); r != 3 {
+		t.Errorf("Rank() = %d, want 3", r)
+	}
+	inv, err := s.Inverse()
+	want, _ := m.Inverse()
+	if err != nil || !reflect.DeepEqual(inv, want) {
+		t.Errorf("Inverse() = %v, %v, want %v", inv, err, want)
+	}
+	if _, err := NewSparse(2, 3).Determinant(); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("Determinant() of 2x3 error = %v, want ErrNotSquare", err)
+	}
+	if _, err := NewSparse(2, 2).Inverse(); !errors.Is(err, ErrSingular) {
+		t.Errorf("Inverse() of zero matrix error = %v, want ErrSingular", err)
+	}
+}
+
+func BenchmarkSparseMul(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping benchmark in short mode.")
+	}
+	rng := rand.New(rand.NewSource(1))
+	s := randomSparse(rng, 400, 400, 100).Sparse()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Mul(s)
+	}
+}
+
+func BenchmarkDenseMul(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping benchmark in short mode.")
+	}
+	rng := rand.New(rand.NewSource(1))
+	m := randomSparse(rng, 400, 400, 100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Mul(m)
+	}
+}
@@ -0,0 +1,138 @@
+package letter
+
+import (
+	"math"
+	"sort"
+)
+
+// Merge adds other's counts to m.
+func (m FreqMap) Merge(other FreqMap) {
+	for r, n := range other {
+		m[r] += n
+	}
+}
+
+// Subtract takes other's counts away from m. Runes whose counts fall to zero
+// or below are removed, so m is left with what it had more of than other.
+func (m FreqMap) Subtract(other FreqMap) {
+	for r, n := range other {
+		if _, ok := m[r]; !ok {
+			continue
+		}
+		if m[r] -= n; m[r] <= 0 {
+			delete(m, r)
+		}
+	}
+}
+
+// Total returns the sum of all the counts in m.
+func (m FreqMap) Total() int {
+	total := 0
+	for _, n := range m {
+		total += n
+	}
+	return total
+}
+
+// RuneCount is a rune and how often it was found.
+type RuneCount struct {
+	Rune  rune
+	Count int
+}
+
+// TopK returns the k most frequent runes, most frequent first. Runes found
+// equally often come in order of their code points. If there are fewer than k
+// runes, they are all returned.
+func (m FreqMap) TopK(k int) []RuneCount {
+	return topK(m, k, func(r rune, n int) RuneCount { return RuneCount{r, n} })
+}
+
+// topK returns the k keys of counts with the highest counts, highest first and
+// equal counts in key order, each paired with its count by pair. It returns
+// nil if k is zero or less.
+func topK[K rune | string, T any](counts map[K]int, k int, pair func(K, int) T) []T {
+	if k <= 0 {
+		return nil
+	}
+	keys := make([]K, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if k < len(keys) {
+		keys = keys[:k]
+	}
+	top := make([]T, len(keys))
+	for i, key := range keys {
+		top[i] = pair(key, counts[key])
+	}
+	return top
+}
+
+// Distribution gives the probability of each rune, summing to one.
+type Distribution map[rune]float64
+
+// Normalize returns the share of the total that each rune's count makes up.
+// Runes with counts below one are left out; if there are none left, the
+// Distribution is empty.
+func (m FreqMap) Normalize() Distribution {
+	total := 0
+	for _, n := range m {
+		if n > 0 {
+			total += n
+		}
+	}
+	d := Distribution{}
+	if total == 0 {
+		return d
+	}
+	for r, n := range m {
+		if n > 0 {
+			d[r] = float64(n) / float64(total)
+		}
+	}
+	return d
+}
+
+// Entropy returns the Shannon entropy of m's Normalize distribution, in bits:
+// how many bits it takes on average to say which rune comes next, if each is
+// drawn independently. It is 0 for an empty FreqMap.
+func (m FreqMap) Entropy() float64 {
+	h := 0.0
+	for _, p := range m.Normalize() {
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// ChiSquared returns Pearson's chi-squared statistic for m against the
+// expected distribution: the smaller it is, the better m fits. Only the runes
+// expected gives a probability above zero are compared, since the statistic
+// can't be worked out for the others, and the expected counts are scaled to
+// the total of those runes in m. It is 0 if m has none of them.
+func (m FreqMap) ChiSquared(expected Distribution) float64 {
+	total := 0
+	for r, p := range expected {
+		if p > 0 && m[r] > 0 {
+			total += m[r]
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	chi := 0.0
+	for r, p := range expected {
+		if p <= 0 {
+			continue
+		}
+		e := p * float64(total)
+		d := float64(m[r]) - e
+		chi += d * d / e
+	}
+	return chi
+}
@@ -0,0 +1,99 @@
+package letter
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMergeSubtract(t *testing.T) {
+	m := FreqMap{'a': 3, 'b': 1}
+	m.Merge(FreqMap{'a': 1, 'c': 2})
+	if want := (FreqMap{'a': 4, 'b': 1, 'c': 2}); !reflect.DeepEqual(m, want) {
+		t.Errorf("after Merge, m = %v, want %v", m, want)
+	}
+	m.Subtract(FreqMap{'a': 1, 'b': 1, 'c': 5, 'd': 1})
+	if want := (FreqMap{'a': 3}); !reflect.DeepEqual(m, want) {
+		t.Errorf("after Subtract, m = %v, want %v", m, want)
+	}
+
+	// Merging the counts of parts gives the count of the whole, and
+	// subtracting a part leaves the count of the rest.
+	whole := Frequency(euro + dutch + us)
+	parts := Frequency(euro)
+	parts.Merge(Frequency(dutch))
+	parts.Merge(Frequency(us))
+	if !reflect.DeepEqual(parts, whole) {
+		t.Error("merged counts differ from Frequency of the whole")
+	}
+	whole.Subtract(Frequency(euro + dutch))
+	if want := Frequency(us); !reflect.DeepEqual(whole, want) {
+		t.Errorf("after Subtract, whole = %v, want %v", whole, want)
+	}
+}
+
+func TestTotal(t *testing.T) {
+	for _, s := range []string{"", "abc", euro, "€𝄞ж"} {
+		if got, want := Frequency(s).Total(), len([]rune(s)); got != want {
+			t.Errorf("Frequency(%q).Total() = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	m := Frequency("mississippi")
+	for _, tt := range []struct {
+		k    int
+		want []RuneCount
+	}{
+		{-1, nil},
+		{0, nil},
+		{2, []RuneCount{{'i', 4}, {'s', 4}}},
+		{5, []RuneCount{{'i', 4}, {'s', 4}, {'p', 2}, {'m', 1}}},
+	} {
+		if got := m.TopK(tt.k); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TopK(%d) = %v, want %v", tt.k, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeEntropy(t *testing.T) {
+	for _, tt := range []struct {
+		m       FreqMap
+		want    Distribution
+		entropy float64
+	}{
+		{FreqMap{}, Distribution{}, 0},
+		{FreqMap{'a': 7}, Distribution{'a': 1}, 0},
+		{FreqMap{'a': 1, 'b': 1}, Distribution{'a': 0.5, 'b': 0.5}, 1},
+		{FreqMap{'a': 2, 'b': 1, 'c': 1}, Distribution{'a': 0.5, 'b': 0.25, 'c': 0.25}, 1.5},
+		{FreqMap{'a': 1, 'b': 1, 'c': 1, 'd': 1, 'e': 0, 'f': -2}, Distribution{'a': 0.25, 'b': 0.25, 'c': 0.25, 'd': 0.25}, 2},
+	} {
+		if got := tt.m.Normalize(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v.Normalize() = %v, want %v", tt.m, got, tt.want)
+		}
+		if got := tt.m.Entropy(); math.Abs(got-tt.entropy) > 1e-12 {
+			t.Errorf("%v.Entropy() = %v, want %v", tt.m, got, tt.entropy)
+		}
+	}
+}
+
+func TestChiSquared(t *testing.T) {
+	fair := Distribution{'h': 0.5, 't': 0.5}
+	for _, tt := range []struct {
+		m        FreqMap
+		expected Distribution
+		want     float64
+	}{
+		{FreqMap{'h': 50, 't': 50}, fair, 0},
+		{FreqMap{'h': 60, 't': 40}, fair, 4},
+		// Runes the expected distribution doesn't cover are ignored.
+		{FreqMap{'h': 60, 't': 40, 'x': 1000}, fair, 4},
+		{FreqMap{'h': 30, 'x': 5}, Distribution{'h': 0.25, 't': 0.75, 'x': 0}, 90},
+		{FreqMap{'x': 5}, fair, 0},
+	} {
+		if got := tt.m.ChiSquared(tt.expected); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%v.ChiSquared(%v) = %v, want %v", tt.m, tt.expected, got, tt.want)
+		}
+	}
+}
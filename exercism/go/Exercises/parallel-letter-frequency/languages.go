@@ -0,0 +1,129 @@
+package letter
+
+import (
+	"sort"
+	"unicode"
+)
+
+// Language is a language's typical letter frequencies, for GuessLanguage.
+type Language struct {
+	Name string
+	// Letters is how often each lower case letter turns up in ordinary text
+	// in the language, accented letters counted as letters of their own.
+	Letters Distribution
+}
+
+// The languages GuessLanguage knows about out of the box. Their letter
+// frequencies are the usual published figures, taken from large bodies of
+// text in each language.
+var (
+	English = Language{"English", profile(map[rune]float64{
+		'a': 8.167, 'b': 1.492, 'c': 2.782, 'd': 4.253, 'e': 12.702, 'f': 2.228,
+		'g': 2.015, 'h': 6.094, 'i': 6.966, 'j': 0.153, 'k': 0.772, 'l': 4.025,
+		'm': 2.406, 'n': 6.749, 'o': 7.507, 'p': 1.929, 'q': 0.095, 'r': 5.987,
+		's': 6.327, 't': 9.056, 'u': 2.758, 'v': 0.978, 'w': 2.360, 'x': 0.150,
+		'y': 1.974, 'z': 0.074,
+	})}
+	French = Language{"French", profile(map[rune]float64{
+		'a': 7.636, 'b': 0.901, 'c': 3.260, 'd': 3.669, 'e': 14.715, 'f': 1.066,
+		'g': 0.866, 'h': 0.737, 'i': 7.529, 'j': 0.613, 'k': 0.074, 'l': 5.456,
+		'm': 2.968, 'n': 7.095, 'o': 5.796, 'p': 2.521, 'q': 1.362, 'r': 6.693,
+		's': 7.948, 't': 7.244, 'u': 6.311, 'v': 1.838, 'w': 0.049, 'x': 0.427,
+		'y': 0.128, 'z': 0.326,
+		'à': 0.486, 'â': 0.051, 'œ': 0.018, 'ç': 0.085, 'è': 0.271, 'é': 1.504,
+		'ê': 0.218, 'ë': 0.008, 'î': 0.045, 'ï': 0.005, 'ô': 0.023, 'ù': 0.058,
+		'û': 0.060,
+	})}
+	German = Language{"German", profile(map[rune]float64{
+		'a': 6.516, 'b': 1.886, 'c': 2.732, 'd': 5.076, 'e': 16.396, 'f': 1.656,
+		'g': 3.009, 'h': 4.577, 'i': 6.550, 'j': 0.268, 'k': 1.417, 'l': 3.437,
+		'm': 2.534, 'n': 9.776, 'o': 2.594, 'p': 0.670, 'q': 0.018, 'r': 7.003,
+		's': 7.270, 't': 6.154, 'u': 4.166, 'v': 0.846, 'w': 1.921, 'x': 0.034,
+		'y': 0.039, 'z': 1.134,
+		'ä': 0.578, 'ö': 0.443, 'ß': 0.307, 'ü': 0.995,
+	})}
+	Spanish = Language{"Spanish", profile(map[rune]float64{
+		'a': 11.525, 'b': 2.215, 'c': 4.019, 'd': 5.010, 'e': 12.181, 'f': 0.692,
+		'g': 1.768, 'h': 0.703, 'i': 6.247, 'j': 0.493, 'k': 0.011, 'l': 4.967,
+		'm': 3.157, 'n': 6.712, 'o': 8.683, 'p': 2.510, 'q': 0.877, 'r': 6.871,
+		's': 7.977, 't': 4.632, 'u': 2.927, 'v': 1.138, 'w': 0.017, 'x': 0.215,
+		'y': 1.008, 'z': 0.467,
+		'á': 0.502, 'é': 0.433, 'í': 0.725, 'ñ': 0.311, 'ó': 0.827, 'ú': 0.168,
+		'ü': 0.012,
+	})}
+)
+
+// Languages are the languages GuessLanguage tries when given none.
+var Languages = []Language{English, French, German, Spanish}
+
+// profile turns percentages into a Distribution.
+func profile(percent map[rune]float64) Distribution {
+	total := 0.0
+	for _, p := range percent {
+		total += p
+	}
+	d := make(Distribution, len(percent))
+	for r, p := range percent {
+		d[r] = p / total
+	}
+	return d
+}
+
+// unseen is the probability given to a letter that a language's profile
+// doesn't have but another's does, so that finding it counts against the
+// language rather than being ignored.
+const unseen = 1e-4
+
+// Guess is how well a text fits a language.
+type Guess struct {
+	Language string
+	// ChiSquared is the chi-squared statistic of the text's letters against
+	// the language's; the smaller, the better the fit.
+	ChiSquared float64
+}
+
+// GuessLanguage compares the letters of text with each of languages, or with
+// Languages if there are none, and returns how well it fits each, best first.
+// Letters are counted in NFC with case folded, and only letters in at least one
+// of the languages count. It returns nil if text has none of them.
+//
+// A few hundred letters are usually enough to tell the languages apart; a
+// short text may well fit the wrong one best.
+func GuessLanguage(text string, languages ...Language) []Guess {
+	if len(languages) == 0 {
+		languages = Languages
+	}
+	alphabet := map[rune]bool{}
+	for _, l := range languages {
+		for r := range l.Letters {
+			alphabet[r] = true
+		}
+	}
+	counts := FrequencyWithOptions(text, Options{
+		Normalization: NFC,
+		FoldCase:      true,
+		Only:          []*unicode.RangeTable{unicode.Letter},
+	})
+	for r := range counts {
+		if !alphabet[r] {
+			delete(counts, r)
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	guesses := make([]Guess, len(languages))
+	for i, l := range languages {
+		expected := make(Distribution, len(alphabet))
+		for r := range alphabet {
+			expected[r] = unseen
+		}
+		for r, p := range l.Letters {
+			expected[r] = p
+		}
+		guesses[i] = Guess{l.Name, counts.ChiSquared(expected)}
+	}
+	sort.SliceStable(guesses, func(i, j int) bool { return guesses[i].ChiSquared < guesses[j].ChiSquared })
+	return guesses
+}
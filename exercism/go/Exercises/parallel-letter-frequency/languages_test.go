@@ -0,0 +1,70 @@
+package letter
+
+import (
+	"math"
+	"testing"
+)
+
+const (
+	french = `Allons enfants de la Patrie,
+Le jour de gloire est arrivé !
+Contre nous de la tyrannie
+L'étendard sanglant est levé,
+Entendez-vous dans les campagnes
+Mugir ces féroces soldats ?
+Ils viennent jusque dans vos bras
+Égorger vos fils, vos compagnes !
+Aux armes, citoyens, formez vos bataillons,
+Marchons, marchons ! Qu'un sang impur abreuve nos sillons !`
+
+	spanish = `En un lugar de la Mancha, de cuyo nombre no quiero acordarme, no ha
+mucho tiempo que vivía un hidalgo de los de lanza en astillero, adarga
+antigua, rocín flaco y galgo corredor. Una olla de algo más vaca que
+carnero, salpicón las más noches, duelos y quebrantos los sábados,
+lantejas los viernes, algún palomino de añadidura los domingos,
+consumían las tres partes de su hacienda.`
+)
+
+func TestProfiles(t *testing.T) {
+	for _, l := range Languages {
+		total := 0.0
+		for _, p := range l.Letters {
+			total += p
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("%s letters add up to %v", l.Name, total)
+		}
+	}
+}
+
+func TestGuessLanguage(t *testing.T) {
+	for _, tt := range []struct {
+		text string
+		want string
+	}{
+		{us, "English"},
+		{euro, "German"},
+		{french, "French"},
+		{spanish, "Spanish"},
+	} {
+		guesses := GuessLanguage(tt.text)
+		if len(guesses) != len(Languages) {
+			t.Fatalf("GuessLanguage returned %d guesses, want %d", len(guesses), len(Languages))
+		}
+		if guesses[0].Language != tt.want {
+			t.Errorf("GuessLanguage(%.20q...) = %v, want %s first", tt.text, guesses, tt.want)
+		}
+		for i := 1; i < len(guesses); i++ {
+			if guesses[i].ChiSquared < guesses[i-1].ChiSquared {
+				t.Errorf("guesses out of order: %v", guesses)
+			}
+		}
+	}
+
+	if got := GuessLanguage("12345 !?", English, German); got != nil {
+		t.Errorf("GuessLanguage without letters = %v, want nil", got)
+	}
+	if got := GuessLanguage("Grüße aus Köln", English, Spanish); len(got) != 2 {
+		t.Errorf("GuessLanguage with two languages = %v", got)
+	}
+}
@@ -0,0 +1,77 @@
+package letter
+
+import (
+	"fmt"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization is a Unicode normalization form, which makes text that looks
+// the same also count the same: 'é' written as one rune or as 'e' followed by
+// a combining acute accent, say.
+type Normalization int
+
+const (
+	// NoNormalization counts runes as they are.
+	NoNormalization Normalization = iota
+	// NFC composes letters and marks into single runes wherever it can.
+	NFC
+	// NFD decomposes letters into a base letter and combining marks.
+	NFD
+	// NFKC is NFC that also replaces compatibility characters, such as the
+	// ligature 'ﬁ' or fullwidth 'Ａ', with their plain equivalents.
+	NFKC
+	// NFKD is NFD that also replaces compatibility characters.
+	NFKD
+)
+
+func (n Normalization) String() string {
+	switch n {
+	case NoNormalization:
+		return "none"
+	case NFC:
+		return "NFC"
+	case NFD:
+		return "NFD"
+	case NFKC:
+		return "NFKC"
+	case NFKD:
+		return "NFKD"
+	}
+	return fmt.Sprintf("Normalization(%d)", int(n))
+}
+
+// form returns the norm.Form for n, which must not be NoNormalization.
+func (n Normalization) form() norm.Form {
+	switch n {
+	case NFC:
+		return norm.NFC
+	case NFD:
+		return norm.NFD
+	case NFKC:
+		return norm.NFKC
+	case NFKD:
+		return norm.NFKD
+	}
+	panic(fmt.Sprintf("letter: no normal form for %v", n))
+}
+
+// normalize returns text in the given form.
+func normalize(text string, form Normalization) []rune {
+	return []rune(form.form().String(text))
+}
+
+// boundarySplitPoint returns a split function for text that is going to be put
+// into the given form. It is splitPoint, except that it cuts at the last place
+// the form says it is safe to, so that no letter is parted from the marks that
+// follow it. It falls back on splitPoint if there is no such place.
+func boundarySplitPoint(form Normalization) func([]byte) int {
+	f := form.form()
+	return func(data []byte) int {
+		end := splitPoint(data)
+		if i := f.LastBoundary(data[:end]); i > 0 {
+			return i
+		}
+		return end
+	}
+}
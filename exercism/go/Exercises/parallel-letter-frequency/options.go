@@ -0,0 +1,121 @@
+package letter
+
+import (
+	"context"
+	"io"
+	"sync"
+	"unicode"
+)
+
+// Options change what counts as the same rune, and which runes are counted at
+// all. The zero Options count every rune as it is, as Frequency does.
+type Options struct {
+	// Normalization, if set, puts the text into that normal form before
+	// counting. With NFD or NFKD, accented letters are counted as the base
+	// letter and the accent separately.
+	Normalization Normalization
+
+	// FoldCase counts upper and lower case forms of a letter together, under
+	// the lower case.
+	FoldCase bool
+
+	// Only, if not empty, counts only the runes in at least one of these
+	// tables, such as unicode.Letter.
+	Only []*unicode.RangeTable
+
+	// Skip leaves out the runes in any of these tables, such as unicode.Punct
+	// or unicode.White_Space.
+	Skip []*unicode.RangeTable
+}
+
+// plain reports whether the options leave every rune as it is.
+func (o Options) plain() bool {
+	return o.Normalization == NoNormalization && !o.FoldCase && len(o.Only) == 0 && len(o.Skip) == 0
+}
+
+// count adds the runes of text to m, as the options say.
+func (o Options) count(m FreqMap, text string) {
+	if o.plain() {
+		for _, r := range text {
+			m[r]++
+		}
+		return
+	}
+	var runes []rune
+	if o.Normalization != NoNormalization {
+		runes = normalize(text, o.Normalization)
+	} else {
+		runes = []rune(text)
+	}
+	for _, r := range runes {
+		if o.FoldCase {
+			r = unicode.ToLower(unicode.ToUpper(r))
+		}
+		if len(o.Only) > 0 && !unicode.IsOneOf(o.Only, r) {
+			continue
+		}
+		if unicode.IsOneOf(o.Skip, r) {
+			continue
+		}
+		m[r]++
+	}
+}
+
+// FrequencyWithOptions is Frequency, counting runes as opts say.
+func FrequencyWithOptions(s string, opts Options) FreqMap {
+	m := FreqMap{}
+	opts.count(m, s)
+	return m
+}
+
+// ConcurrentFrequencyWithOptions is ConcurrentFrequency, counting runes as
+// opts say.
+func ConcurrentFrequencyWithOptions(l []string, opts Options) FreqMap {
+
+	// Create a buffered channel to alleviate some pressure between our
+	// goroutines and our channel.
+	freqChan := make(chan FreqMap, len(l))
+
+	// Create a WaitGroup, which acts as a way of controlling concurrency.
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(len(l))
+
+	// This anonymous function acts as a way to close our channel once all of
+	// our goroutines are finished.
+	go func() {
+		waitGroup.Wait()
+		close(freqChan)
+	}()
+
+	// Create a number of goroutines equal to the number of strings in l
+	// When the goroutine has filled its channel, it decrements our wait count.
+	for i := 0; i < len(l); i++ {
+		y := l[i]
+		go func(y string) {
+			defer waitGroup.Done()
+			freqChan <- FrequencyWithOptions(y, opts)
+		}(y)
+	}
+
+	// Create a blank map to store the results of our goroutines.
+	finalMap := make(FreqMap)
+	// Range over the contents of the channels we have painstakingly filled...
+	for freqMap := range freqChan {
+		// ... and add the contents to our final tally.
+		for k, v := range freqMap {
+			finalMap[k] += v
+		}
+	}
+	return finalMap
+}
+
+// FrequencyReaderWithOptions is FrequencyReader, counting runes as opts say.
+// When normalizing, chunks are cut before a letter rather than just between
+// runes, so that letters and their marks are always normalized together.
+func FrequencyReaderWithOptions(ctx context.Context, r io.Reader, workers int, opts Options) (FreqMap, error) {
+	split := splitPoint
+	if opts.Normalization != NoNormalization {
+		split = boundarySplitPoint(opts.Normalization)
+	}
+	return frequencyReader(ctx, r, workers, split, opts.count)
+}
@@ -0,0 +1,120 @@
+package letter
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestNormalize(t *testing.T) {
+	for _, tt := range []struct {
+		in                   string
+		nfc, nfd, nfkc, nfkd string
+	}{
+		{"é", "é", "é", "é", "é"},
+		{"é", "é", "é", "é", "é"},
+		{"ﬁ", "ﬁ", "ﬁ", "fi", "fi"},
+		{"Ａ²", "Ａ²", "Ａ²", "A2", "A2"},
+		{"Å", "Å", "Å", "Å", "Å"},
+		// Marks are put in canonical order, then composed where they can be.
+		{"ạ́", "ạ́", "ạ́", "ạ́", "ạ́"},
+		{"ḍ̇", "ḍ̇", "ḍ̇", "ḍ̇", "ḍ̇"},
+		{"q̣̇", "q̣̇", "q̣̇", "q̣̇", "q̣̇"},
+		// A second mark of the same class is blocked from the letter.
+		{"é́", "é́", "é́", "é́", "é́"},
+		{"각", "각", "각", "각", "각"},
+		{"각", "각", "각", "각", "각"},
+		{"ϓ", "ϓ", "ϓ", "Ύ", "Ύ"},
+		// Other scripts and symbols, including composites that NFC leaves
+		// decomposed and singletons that always map to another rune.
+		{"\u304C", "\u304C", "\u304B\u3099", "\u304C", "\u304B\u3099"},
+		{"\u0929", "\u0929", "\u0928\u093C", "\u0929", "\u0928\u093C"},
+		{"\u0958", "\u0915\u093C", "\u0915\u093C", "\u0915\u093C", "\u0915\u093C"},
+		{"\uFB1D", "\u05D9\u05B4", "\u05D9\u05B4", "\u05D9\u05B4", "\u05D9\u05B4"},
+		{"\u219A", "\u219A", "\u2190\u0338", "\u219A", "\u2190\u0338"},
+		{"\u2329", "\u3008", "\u3008", "\u3008", "\u3008"},
+	} {
+		for form, want := range map[Normalization]string{NFC: tt.nfc, NFD: tt.nfd, NFKC: tt.nfkc, NFKD: tt.nfkd} {
+			if got := string(normalize(tt.in, form)); got != want {
+				t.Errorf("%v(%q) = %q, want %q", form, tt.in, got, want)
+			}
+		}
+	}
+}
+
+func TestFrequencyWithOptions(t *testing.T) {
+	text := "Éé é, ﬁne!"
+	for _, tt := range []struct {
+		name string
+		opts Options
+		want FreqMap
+	}{
+		{"none", Options{}, Frequency(text)},
+		{"NFC", Options{Normalization: NFC},
+			FreqMap{'É': 1, 'é': 2, ' ': 2, ',': 1, 'ﬁ': 1, 'n': 1, 'e': 1, '!': 1}},
+		{"NFC folded letters", Options{Normalization: NFC, FoldCase: true, Only: []*unicode.RangeTable{unicode.Letter}},
+			FreqMap{'é': 3, 'ﬁ': 1, 'n': 1, 'e': 1}},
+		{"NFKD letters", Options{Normalization: NFKD, FoldCase: true, Only: []*unicode.RangeTable{unicode.Letter}},
+			FreqMap{'e': 4, 'f': 1, 'i': 1, 'n': 1}},
+		{"skip", Options{Skip: []*unicode.RangeTable{unicode.Punct, unicode.White_Space}},
+			FreqMap{'É': 1, 'é': 1, 'e': 2, '́': 1, 'ﬁ': 1, 'n': 1}},
+		{"fold", Options{FoldCase: true, Only: []*unicode.RangeTable{unicode.Upper}}, FreqMap{}},
+	} {
+		if got := FrequencyWithOptions(text, tt.opts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: FrequencyWithOptions = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Folding covers letters whose cases don't simply pair up.
+	got := FrequencyWithOptions("ſSsKk ΣσςÆæ", Options{FoldCase: true, Skip: []*unicode.RangeTable{unicode.White_Space}})
+	if want := (FreqMap{'s': 3, 'k': 2, 'σ': 3, 'æ': 2}); !reflect.DeepEqual(got, want) {
+		t.Errorf("folded = %v, want %v", got, want)
+	}
+}
+
+func TestConcurrentFrequencyWithOptions(t *testing.T) {
+	opts := Options{Normalization: NFKC, FoldCase: true, Only: []*unicode.RangeTable{unicode.Letter}}
+	want := FrequencyWithOptions(euro+dutch+us, opts)
+	if got := ConcurrentFrequencyWithOptions([]string{euro, dutch, us}, opts); !reflect.DeepEqual(got, want) {
+		t.Errorf("ConcurrentFrequencyWithOptions = %v, want %v", got, want)
+	}
+}
+
+func TestFrequencyReaderWithOptions(t *testing.T) {
+	// Letters followed by long runs of marks, so that chunks can't be cut
+	// just anywhere without splitting a letter from its accents.
+	text := strings.Repeat("ẹ́ﬁ각가 ", 20000)
+	for _, opts := range []Options{
+		{Normalization: NFC},
+		{Normalization: NFKD, FoldCase: true},
+		{Normalization: NFKC, Skip: []*unicode.RangeTable{unicode.White_Space}},
+	} {
+		want := FrequencyWithOptions(text, opts)
+		got, err := FrequencyReaderWithOptions(context.Background(), strings.NewReader(text), 3, opts)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%+v: FrequencyReaderWithOptions = %v, %v, want %v", opts, got, err, want)
+		}
+	}
+}
+
+func TestBoundarySplitPoint(t *testing.T) {
+	for _, tt := range []struct {
+		data string
+		want int
+	}{
+		{"abc", 2},
+		{"ab́", 1},
+		{"aḅ́", 1},
+		{"á", 2 + 1},
+		{"x가", 1},
+		{"xy\xe2\x82", 1},
+	} {
+		for _, form := range []Normalization{NFC, NFD, NFKC, NFKD} {
+			if got := boundarySplitPoint(form)([]byte(tt.data)); got != tt.want {
+				t.Errorf("boundarySplitPoint(%v)(%q) = %d, want %d", form, tt.data, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package letter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"unicode/utf8"
+)
+
+// chunkSize is how many bytes FrequencyReader hands to a worker at a time.
+const chunkSize = 64 << 10
+
+// FrequencyReader counts the frequency of each rune read from r, which can be
+// far too big to hold in memory at once. It reads r a chunk at a time and
+// shares the chunks out among a fixed pool of workers, merging their counts as
+// they come back. A workers count below one means one per CPU.
+//
+// Chunks are cut between runes, never through one, so the result is the same
+// as Frequency would give for the whole text, invalid UTF-8 included. If ctx
+// is cancelled before the counting is done, FrequencyReader stops and returns
+// ctx.Err().
+func FrequencyReader(ctx context.Context, r io.Reader, workers int) (FreqMap, error) {
+	return FrequencyReaderWithOptions(ctx, r, workers, Options{})
+}
+
+// frequencyReader does the work of FrequencyReader, cutting chunks with split
+// and counting each with count.
+func frequencyReader(ctx context.Context, r io.Reader, workers int, split func([]byte) int, count func(FreqMap, string)) (FreqMap, error) {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunks := make(chan []byte, workers)
+	results := make(chan FreqMap, workers)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(chunks)
+		readErr <- readChunks(ctx, r, chunks, split)
+	}()
+
+	// Create a WaitGroup, so the results channel can be closed once every
+	// worker has run out of chunks.
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer waitGroup.Done()
+			for chunk := range chunks {
+				freqMap := FreqMap{}
+				count(freqMap, string(chunk))
+				select {
+				case results <- freqMap:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		waitGroup.Wait()
+		close(results)
+	}()
+
+	finalMap := FreqMap{}
+	for freqMap := range results {
+		finalMap.Merge(freqMap)
+	}
+	if err := <-readErr; err != nil {
+		return nil, err
+	}
+	// Workers give up on ctx being cancelled too, so a count finished after
+	// that may be missing some chunks.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return finalMap, nil
+}
+
+// readChunks reads r into chunks of about chunkSize bytes, each cut where split
+// says, and sends them on. It stops early if ctx is cancelled.
+func readChunks(ctx context.Context, r io.Reader, chunks chan<- []byte, split func([]byte) int) error {
+	var carry []byte
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Each chunk needs a buffer of its own, as a worker may still be
+		// counting the last one.
+		buf := make([]byte, len(carry)+chunkSize)
+		copy(buf, carry)
+		n, err := io.ReadFull(r, buf[len(carry):])
+		data := buf[:len(carry)+n]
+		done := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !done {
+			return fmt.Errorf("letter: reading: %w", err)
+		}
+
+		carry = nil
+		if !done {
+			cut := split(data)
+			carry = append(carry, data[cut:]...)
+			data = data[:cut]
+		}
+		if len(data) > 0 {
+			select {
+			case chunks <- data:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// splitPoint returns where to cut data so that a rune at the end that has only
+// partly been read is left for the next chunk.
+func splitPoint(data []byte) int {
+	for i := len(data) - 1; i >= 0 && i > len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(data)
+}
@@ -0,0 +1,110 @@
+package letter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// bigText is several chunks long and full of multi-byte runes, so chunk
+// boundaries are bound to fall in the middle of some.
+var bigText = strings.Repeat(euro+dutch+us+"€𝄞ж", 400) + "\xe2\x82" + "x\xff"
+
+func TestFrequencyReader(t *testing.T) {
+	if len(bigText) < 3*chunkSize {
+		t.Fatalf("test text is only %d bytes", len(bigText))
+	}
+	want := Frequency(bigText)
+	for _, tt := range []struct {
+		name string
+		r    io.Reader
+	}{
+		{"whole", strings.NewReader(bigText)},
+		{"a byte at a time", iotest.OneByteReader(strings.NewReader(bigText))},
+		{"half reads", iotest.HalfReader(strings.NewReader(bigText))},
+	} {
+		got, err := FrequencyReader(context.Background(), tt.r, 3)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: counts differ from Frequency (error %v)", tt.name, err)
+		}
+	}
+	got, err := FrequencyReader(context.Background(), strings.NewReader(""), 0)
+	if err != nil || len(got) != 0 {
+		t.Errorf("FrequencyReader of empty input = %v, %v", got, err)
+	}
+	for _, workers := range []int{1, 2, 8} {
+		got, err := FrequencyReader(context.Background(), strings.NewReader(bigText), workers)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%d workers: counts differ from Frequency (error %v)", workers, err)
+		}
+	}
+}
+
+func TestSplitPoint(t *testing.T) {
+	for _, tt := range []struct {
+		data string
+		want int
+	}{
+		{"abc", 3},
+		{"ab\xe2\x82", 2},
+		{"ab\xe2\x82\xac", 5},
+		{"a\xf0\x9d\x84", 1},
+		{"a\xf0\x9d\x84\x9e", 5},
+		{"ab\xe2A", 4},
+		{"\x82\x82\x82\x82", 4},
+		{"", 0},
+	} {
+		if got := splitPoint([]byte(tt.data)); got != tt.want {
+			t.Errorf("splitPoint(%q) = %d, want %d", tt.data, got, tt.want)
+		}
+	}
+}
+
+// endless is a reader that never runs out of text.
+type endless struct{}
+
+func (endless) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'a'
+	}
+	return len(p), nil
+}
+
+func TestFrequencyReaderCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := FrequencyReader(ctx, endless{}, 4)
+		done <- err
+	}()
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("FrequencyReader after cancel returned %v, want %v", err, context.Canceled)
+	}
+
+	if _, err := FrequencyReader(ctx, strings.NewReader("abc"), 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("FrequencyReader with a cancelled context returned %v", err)
+	}
+}
+
+func TestFrequencyReaderError(t *testing.T) {
+	boom := errors.New("boom")
+	r := io.MultiReader(strings.NewReader(bigText), iotest.ErrReader(boom))
+	if _, err := FrequencyReader(context.Background(), r, 2); !errors.Is(err, boom) {
+		t.Errorf("FrequencyReader returned %v, want %v", err, boom)
+	}
+}
+
+func BenchmarkFrequencyReader(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping benchmark in short mode.")
+	}
+	b.SetBytes(int64(len(bigText)))
+	for i := 0; i < b.N; i++ {
+		FrequencyReader(context.Background(), strings.NewReader(bigText), 0)
+	}
+}
@@ -0,0 +1,104 @@
+package letter
+
+import (
+	"fmt"
+	"sync"
+	"unicode/utf8"
+)
+
+// TokenMap records the frequency of each token in a given text.
+type TokenMap map[string]int
+
+// A Tokenizer splits text into tokens, calling emit with each in turn. The
+// same token may be emitted any number of times.
+type Tokenizer func(text string, emit func(token string))
+
+// RuneNGrams returns a Tokenizer for every run of n consecutive runes in a
+// text, overlapping: "abcd" has the bigrams "ab", "bc" and "cd". A text of
+// fewer than n runes has none. It panics if n is less than one.
+func RuneNGrams(n int) Tokenizer {
+	if n < 1 {
+		panic(fmt.Sprintf("letter: n-gram size %d is less than 1", n))
+	}
+	return func(text string, emit func(string)) {
+		// starts holds where each of the last n runes began, as a ring.
+		starts := make([]int, n)
+		seen := 0
+		for i := range text {
+			starts[seen%n] = i
+			seen++
+			if seen >= n {
+				// The oldest start is the one about to be overwritten.
+				start := starts[seen%n]
+				_, size := utf8.DecodeRuneInString(text[i:])
+				emit(text[start : i+size])
+			}
+		}
+	}
+}
+
+// ByteNGrams returns a Tokenizer for every run of n consecutive bytes in a
+// text, overlapping, whether or not they cut through a rune. It panics if n is
+// less than one.
+func ByteNGrams(n int) Tokenizer {
+	if n < 1 {
+		panic(fmt.Sprintf("letter: n-gram size %d is less than 1", n))
+	}
+	return func(text string, emit func(string)) {
+		for i := 0; i+n <= len(text); i++ {
+			emit(text[i : i+n])
+		}
+	}
+}
+
+// TokenFrequency counts the frequency of each token t finds in a given text,
+// and returns this data as a TokenMap.
+func TokenFrequency(s string, t Tokenizer) TokenMap {
+	m := TokenMap{}
+	t(s, func(token string) {
+		m[token]++
+	})
+	return m
+}
+
+// ConcurrentTokenFrequency counts the frequency of each token t finds in the
+// given strings, by making use of concurrency. Each string is tokenized on its
+// own, so no token spans two of them.
+func ConcurrentTokenFrequency(l []string, t Tokenizer) TokenMap {
+	tokenChan := make(chan TokenMap, len(l))
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(len(l))
+	go func() {
+		waitGroup.Wait()
+		close(tokenChan)
+	}()
+
+	for _, s := range l {
+		go func(s string) {
+			defer waitGroup.Done()
+			tokenChan <- TokenFrequency(s, t)
+		}(s)
+	}
+
+	finalMap := TokenMap{}
+	for tokenMap := range tokenChan {
+		for k, v := range tokenMap {
+			finalMap[k] += v
+		}
+	}
+	return finalMap
+}
+
+// TokenCount is a token and how often it was found.
+type TokenCount struct {
+	Token string
+	Count int
+}
+
+// TopK returns the k most frequent tokens, most frequent first. Tokens found
+// equally often come in byte order. If there are fewer than k tokens, they are
+// all returned.
+func (m TokenMap) TopK(k int) []TokenCount {
+	return topK(m, k, func(token string, n int) TokenCount { return TokenCount{token, n} })
+}
@@ -0,0 +1,123 @@
+package letter
+
+import (
+	"reflect"
+	"testing"
+)
+
+// tokens returns everything t finds in text, in order.
+func tokens(t Tokenizer, text string) []string {
+	var found []string
+	t(text, func(token string) { found = append(found, token) })
+	return found
+}
+
+func TestNGrams(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		t    Tokenizer
+		text string
+		want []string
+	}{
+		{"rune unigrams", RuneNGrams(1), "aé", []string{"a", "é"}},
+		{"rune bigrams", RuneNGrams(2), "abcd", []string{"ab", "bc", "cd"}},
+		{"rune trigrams", RuneNGrams(3), "schön€", []string{"sch", "chö", "hön", "ön€"}},
+		{"too short", RuneNGrams(3), "ab", nil},
+		{"byte bigrams", ByteNGrams(2), "aé", []string{"a\xc3", "é"}},
+		{"byte trigrams", ByteNGrams(3), "abcd", []string{"abc", "bcd"}},
+		{"bytes too short", ByteNGrams(5), "abcd", nil},
+	} {
+		if got := tokens(tt.t, tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s of %q = %q, want %q", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNGramsPanic(t *testing.T) {
+	for _, ngrams := range []func(int) Tokenizer{RuneNGrams, ByteNGrams} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("n-grams of size 0 did not panic")
+				}
+			}()
+			ngrams(0)
+		}()
+	}
+}
+
+func TestWords(t *testing.T) {
+	for _, tt := range []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"  ,. !", nil},
+		{"The quick (“brown”) fox can’t jump 32.3 feet, right?",
+			[]string{"The", "quick", "brown", "fox", "can’t", "jump", "32.3", "feet", "right"}},
+		{"Freude, schöner Götterfunken!", []string{"Freude", "schöner", "Götterfunken"}},
+		{"dawn's early light", []string{"dawn's", "early", "light"}},
+		{"rock'n'roll's 'quoted'", []string{"rock'n'roll's", "quoted"}},
+		{"π≈3,141.59; e.g. a1b2 snake_case", []string{"π", "3,141.59", "e.g", "a1b2", "snake_case"}},
+		{"trailing. 12, 34.", []string{"trailing", "12", "34"}},
+		// Marks stay with their letters.
+		{"café déjà", []string{"café", "déjà"}},
+		{"Привет, мир", []string{"Привет", "мир"}},
+		{"日本語のカタカナ", []string{"日", "本", "語", "の", "カタカナ"}},
+	} {
+		if got := tokens(Words, tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTokenFrequency(t *testing.T) {
+	got := TokenFrequency("to be or not to be", Words)
+	want := TokenMap{"to": 2, "be": 2, "or": 1, "not": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TokenFrequency = %v, want %v", got, want)
+	}
+}
+
+func TestConcurrentTokenFrequency(t *testing.T) {
+	for _, tokenizer := range []Tokenizer{Words, RuneNGrams(2), ByteNGrams(3)} {
+		want := TokenMap{}
+		for _, s := range []string{euro, dutch, us} {
+			for k, v := range TokenFrequency(s, tokenizer) {
+				want[k] += v
+			}
+		}
+		if got := ConcurrentTokenFrequency([]string{euro, dutch, us}, tokenizer); !reflect.DeepEqual(got, want) {
+			t.Errorf("ConcurrentTokenFrequency = %v, want %v", got, want)
+		}
+	}
+	if got := ConcurrentTokenFrequency(nil, Words); len(got) != 0 {
+		t.Errorf("ConcurrentTokenFrequency of nothing = %v", got)
+	}
+}
+
+func TestTokenTopK(t *testing.T) {
+	m := TokenMap{"the": 5, "of": 3, "and": 3, "a": 1}
+	for _, tt := range []struct {
+		k    int
+		want []TokenCount
+	}{
+		{0, nil},
+		{1, []TokenCount{{"the", 5}}},
+		{3, []TokenCount{{"the", 5}, {"and", 3}, {"of", 3}}},
+		{10, []TokenCount{{"the", 5}, {"and", 3}, {"of", 3}, {"a", 1}}},
+	} {
+		if got := m.TopK(tt.k); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TopK(%d) = %v, want %v", tt.k, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkWords(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping benchmark in short mode.")
+	}
+	for i := 0; i < b.N; i++ {
+		TokenFrequency(euro+dutch+us, Words)
+	}
+}
@@ -0,0 +1,160 @@
+package letter
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// wordBreak is a rune's Word_Break property from Unicode's rules for finding
+// word boundaries, UAX #29.
+type wordBreak int
+
+const (
+	wbOther wordBreak = iota
+	wbLetter
+	wbNumeric
+	wbKatakana
+	wbMidLetter
+	wbMidNum
+	wbMidNumLet
+	wbExtendNumLet
+	wbExtend
+)
+
+// The punctuation that may come inside a word, between letters or between
+// digits.
+var (
+	midLetter = map[rune]bool{
+		':': true, '\u00B7': true, '\u0387': true, '\u055F': true, '\u05F4': true,
+		'\u2027': true, '\uFE13': true, '\uFE55': true, '\uFF1A': true,
+	}
+	midNum = map[rune]bool{
+		',': true, ';': true, '\u037E': true, '\u0589': true, '\u060C': true,
+		'\u060D': true, '\u066C': true, '\u07F8': true, '\u2044': true,
+		'\uFE10': true, '\uFE14': true, '\uFE50': true, '\uFE54': true,
+		'\uFF0C': true, '\uFF1B': true,
+	}
+	midNumLet = map[rune]bool{
+		'.': true, '\'': true, '\u2018': true, '\u2019': true, '\u2024': true,
+		'\uFE52': true, '\uFF07': true, '\uFF0E': true,
+	}
+)
+
+// wordBreakOf works out r's Word_Break property from its category and script,
+// which is close enough for the alphabets and digits of most languages.
+// Scripts written without spaces, such as Han, Hiragana and Thai, have no
+// letters in this sense, so each of their runes is a word of its own.
+func wordBreakOf(r rune) wordBreak {
+	switch {
+	case midLetter[r]:
+		return wbMidLetter
+	case midNum[r]:
+		return wbMidNum
+	case midNumLet[r]:
+		return wbMidNumLet
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Cf):
+		return wbExtend
+	case unicode.In(r, unicode.Katakana) || r == '\u30FC':
+		return wbKatakana
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar):
+		return wbOther
+	case unicode.IsLetter(r):
+		return wbLetter
+	case unicode.IsDigit(r):
+		return wbNumeric
+	case unicode.In(r, unicode.Pc) || r == '\u202F':
+		return wbExtendNumLet
+	}
+	return wbOther
+}
+
+// Words is a Tokenizer for the words of a text, found by Unicode's rules for
+// word boundaries. Words may hold apostrophes and other punctuation that joins
+// letters, as in "can't", and numbers may hold separators, as in "3,141.59".
+// Runs of spaces, punctuation and symbols between words are not tokens.
+func Words(text string, emit func(token string)) {
+	start := 0
+	for start < len(text) {
+		end := wordEnd(text[start:]) + start
+		if isWord(text[start:end]) {
+			emit(text[start:end])
+		}
+		start = end
+	}
+}
+
+// wordEnd returns where the word, or run of other runes, that text starts
+// with ends.
+func wordEnd(text string) int {
+	r, end := utf8.DecodeRuneInString(text)
+	if unicode.IsSpace(r) {
+		return end
+	}
+	// Marks and formatting runes belong to the rune before them, and are
+	// otherwise passed over, so prev is the last rune that is not one of
+	// those.
+	prev := wordBreakOf(r)
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		next := wordBreakOf(r)
+		if next == wbExtend {
+			end += size
+			continue
+		}
+		if !joins(prev, next, text[end+size:]) {
+			return end
+		}
+		if next == wbMidLetter || next == wbMidNum || next == wbMidNumLet {
+			// The rune after the punctuation has been checked to join, so
+			// take it too, letting it decide what may follow.
+			after, afterSize := utf8.DecodeRuneInString(text[end+size:])
+			end += size + afterSize
+			prev = wordBreakOf(after)
+			continue
+		}
+		end += size
+		prev = next
+	}
+	return end
+}
+
+// joins reports whether there is no word boundary between runes with the
+// properties prev and next, given the text that follows next.
+func joins(prev, next wordBreak, rest string) bool {
+	switch next {
+	case wbLetter:
+		return prev == wbLetter || prev == wbNumeric || prev == wbExtendNumLet
+	case wbNumeric:
+		return prev == wbLetter || prev == wbNumeric || prev == wbExtendNumLet
+	case wbKatakana:
+		return prev == wbKatakana || prev == wbExtendNumLet
+	case wbExtendNumLet:
+		return prev == wbLetter || prev == wbNumeric || prev == wbKatakana || prev == wbExtendNumLet
+	case wbMidLetter, wbMidNum, wbMidNumLet:
+		// Punctuation joins only if the same kind of rune comes after it as
+		// before it.
+		after := wbOther
+		if rest != "" {
+			r, _ := utf8.DecodeRuneInString(rest)
+			after = wordBreakOf(r)
+		}
+		switch {
+		case prev == wbLetter && after == wbLetter:
+			return next == wbMidLetter || next == wbMidNumLet
+		case prev == wbNumeric && after == wbNumeric:
+			return next == wbMidNum || next == wbMidNumLet
+		}
+	}
+	return false
+}
+
+// isWord reports whether a stretch of text between word boundaries is a word,
+// rather than spaces or punctuation.
+func isWord(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,297 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Result is the result of one game, as handed to a League.
+type Result struct {
+	// Round is the round the game belongs to. It is only used to work out
+	// standings as of a round, and may be left zero.
+	Round      int
+	Home, Away string
+	// Outcome is how the game went for the home team. It is ignored when the
+	// result is Scored, as the score decides it.
+	Outcome              Outcome
+	Scored               bool
+	HomeGoals, AwayGoals int
+}
+
+// ParseResult reads a result from one line in the form Tally reads, such as
+// "Ants;Bees;win" or "Ants;Bees;3-1".
+func ParseResult(line string) (Result, error) {
+	entry, played, err := parseEntry(line)
+	if err != nil {
+		return Result{}, fmt.Errorf("tournament: %v", err)
+	}
+	if !played {
+		return Result{}, fmt.Errorf("tournament: %q has no result", line)
+	}
+	return Result{
+		Home:      entry.teams[0],
+		Away:      entry.teams[1],
+		Outcome:   entry.outcomes[0],
+		Scored:    entry.scored,
+		HomeGoals: entry.goals[0],
+		AwayGoals: entry.goals[1],
+	}, nil
+}
+
+// entry checks the result and turns it into the form tally works with.
+func (r Result) entry() (inputEntry, error) {
+	switch {
+	case r.Home == "" || r.Away == "":
+		return inputEntry{}, fmt.Errorf("tournament: missing team name in %v", r)
+	case r.Home == r.Away:
+		return inputEntry{}, fmt.Errorf("tournament: team %q cannot play itself", r.Home)
+	case r.Round < 0:
+		return inputEntry{}, fmt.Errorf("tournament: negative round %d", r.Round)
+	}
+	if r.Scored {
+		if r.HomeGoals < 0 || r.AwayGoals < 0 {
+			return inputEntry{}, fmt.Errorf("tournament: bad score %d-%d", r.HomeGoals, r.AwayGoals)
+		}
+		return scoredEntry(r.Home, r.Away, [2]int{r.HomeGoals, r.AwayGoals}), nil
+	}
+	entry := inputEntry{teams: [2]string{r.Home, r.Away}}
+	switch r.Outcome {
+	case Win:
+		entry.outcomes = [2]Outcome{Win, Loss}
+	case Loss:
+		entry.outcomes = [2]Outcome{Loss, Win}
+	case Draw:
+		entry.outcomes = [2]Outcome{Draw, Draw}
+	default:
+		return inputEntry{}, fmt.Errorf("tournament: unknown outcome %v", r.Outcome)
+	}
+	return entry, nil
+}
+
+// ResultID names a result recorded in a League.
+type ResultID int
+
+// EventKind says what an Event did to a League.
+type EventKind int
+
+const (
+	// Recorded adds a new result.
+	Recorded EventKind = iota
+	// Undone takes a result back out.
+	Undone
+	// Amended replaces a result with a corrected one.
+	Amended
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case Recorded:
+		return "recorded"
+	case Undone:
+		return "undone"
+	case Amended:
+		return "amended"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// Event is one change to a League, as kept in its log.
+type Event struct {
+	Seq    int // position in the log, from 1
+	Kind   EventKind
+	ID     ResultID
+	Result Result // the result recorded or amended to; zero when Undone
+}
+
+// League keeps standings up to date as results come in one at a time, rather
+// than adding up every game again as Tally does. Recording, undoing or
+// amending a result only touches the totals of the two teams in it, so costs
+// the same however long the season has run; the table is only put in order
+// when Standings is called.
+//
+// Every change is kept in an append-only log of Events, which Replay can play
+// back to rebuild the league as it was at any point.
+type League struct {
+	rules   Rules
+	teams   map[string]*teamResult
+	results map[ResultID]leagueResult
+	events  []Event
+	lastID  ResultID
+	scored  int // how many of the results have a score
+}
+
+// leagueResult is a result in play, along with its entry for tally.
+type leagueResult struct {
+	result Result
+	entry  inputEntry
+}
+
+// NewLeague returns an empty league that scores games with rules.
+func NewLeague(rules Rules) (*League, error) {
+	if err := rules.validate(); err != nil {
+		return nil, err
+	}
+	return &League{
+		rules:   rules,
+		teams:   map[string]*teamResult{},
+		results: map[ResultID]leagueResult{},
+	}, nil
+}
+
+// Replay returns a league built by applying events, in order, to an empty
+// league with the given rules. Passing a prefix of another league's Events
+// gives that league as it stood at that point.
+func Replay(rules Rules, events []Event) (*League, error) {
+	l, err := NewLeague(rules)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		if e.Seq != len(l.events)+1 {
+			return nil, fmt.Errorf("tournament: event %d out of sequence, want %d", e.Seq, len(l.events)+1)
+		}
+		var err error
+		switch e.Kind {
+		case Recorded:
+			if e.ID != l.lastID+1 {
+				return nil, fmt.Errorf("tournament: event %d records result %d, want %d", e.Seq, e.ID, l.lastID+1)
+			}
+			_, err = l.Record(e.Result)
+		case Undone:
+			err = l.Undo(e.ID)
+		case Amended:
+			err = l.Amend(e.ID, e.Result)
+		default:
+			err = fmt.Errorf("tournament: unknown event kind %v", e.Kind)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w (replaying event %d)", err, e.Seq)
+		}
+	}
+	return l, nil
+}
+
+// Record adds a result to the league, and returns the ID to undo or amend it
+// by.
+func (l *League) Record(r Result) (ResultID, error) {
+	entry, err := r.entry()
+	if err != nil {
+		return 0, err
+	}
+	l.lastID++
+	l.results[l.lastID] = leagueResult{r, entry}
+	l.apply(entry, 1)
+	l.log(Recorded, l.lastID, r)
+	return l.lastID, nil
+}
+
+// Undo takes a result back out of the league.
+func (l *League) Undo(id ResultID) error {
+	old, ok := l.results[id]
+	if !ok {
+		return fmt.Errorf("tournament: no result %d", id)
+	}
+	delete(l.results, id)
+	l.apply(old.entry, -1)
+	l.log(Undone, id, Result{})
+	return nil
+}
+
+// Amend replaces a result with a corrected one.
+func (l *League) Amend(id ResultID, r Result) error {
+	old, ok := l.results[id]
+	if !ok {
+		return fmt.Errorf("tournament: no result %d", id)
+	}
+	entry, err := r.entry()
+	if err != nil {
+		return err
+	}
+	l.apply(old.entry, -1)
+	l.results[id] = leagueResult{r, entry}
+	l.apply(entry, 1)
+	l.log(Amended, id, r)
+	return nil
+}
+
+// Result returns the result with the given ID, if it is still in the league.
+func (l *League) Result(id ResultID) (Result, bool) {
+	r, ok := l.results[id]
+	return r.result, ok
+}
+
+// Events returns a copy of the league's log.
+func (l *League) Events() []Event {
+	return append([]Event(nil), l.events...)
+}
+
+// Standings returns the standings with every result so far.
+func (l *League) Standings() Standings {
+	results := make([]teamResult, 0, len(l.teams))
+	for _, t := range l.teams {
+		results = append(results, *t)
+	}
+	l.rules.rank(results, l.entries(func(Result) bool { return true }))
+	return newStandings(results, l.scored > 0)
+}
+
+// StandingsAsOfRound returns the standings counting only the results in rounds
+// up to and including round, as they stand now after any amendments.
+func (l *League) StandingsAsOfRound(round int) Standings {
+	entries := l.entries(func(r Result) bool { return r.Round <= round })
+	return newStandings(tally(entries, l.rules), anyScored(entries))
+}
+
+// entries returns the entries for the results in the league that keep
+// accepts, oldest first.
+func (l *League) entries(keep func(Result) bool) []inputEntry {
+	ids := make([]ResultID, 0, len(l.results))
+	for id, r := range l.results {
+		if keep(r.result) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	entries := make([]inputEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = l.results[id].entry
+	}
+	return entries
+}
+
+// apply adds an entry to the teams' totals, or takes it away again when sign
+// is -1. A team is dropped from the table once it has no games left.
+func (l *League) apply(entry inputEntry, sign int) {
+	if entry.scored {
+		l.scored += sign
+	}
+	for i, team := range entry.teams {
+		t, ok := l.teams[team]
+		if !ok {
+			t = &teamResult{team: team}
+			l.teams[team] = t
+		}
+		t.played += sign
+		switch entry.outcomes[i] {
+		case Win:
+			t.wins += sign
+		case Draw:
+			t.draws += sign
+		case Loss:
+			t.losses += sign
+		}
+		if entry.scored {
+			t.goalsFor += sign * entry.goals[i]
+			t.goalsAgainst += sign * entry.goals[1-i]
+		}
+		t.points += sign * l.rules.points(entry, i)
+		if t.played == 0 {
+			delete(l.teams, team)
+		}
+	}
+}
+
+// log appends an event to the league's log.
+func (l *League) log(kind EventKind, id ResultID, r Result) {
+	l.events = append(l.events, Event{Seq: len(l.events) + 1, Kind: kind, ID: id, Result: r})
+}
@@ -0,0 +1,215 @@
+package tournament
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// leagueFrom records every line of input, in the form Tally reads, as a result
+// of the given round.
+func leagueFrom(t *testing.T, l *League, round int, input string) []ResultID {
+	t.Helper()
+	var ids []ResultID
+	for _, line := range strings.Split(strings.TrimSpace(input), "\n") {
+		r, err := ParseResult(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Round = round
+		id, err := l.Record(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestLeagueMatchesTally(t *testing.T) {
+	for _, tt := range happyTestCases {
+		l, err := NewLeague(DefaultRules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(tt.input, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			r, err := ParseResult(line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := l.Record(r); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var buffer bytes.Buffer
+		if err := (TextRenderer{}).Render(&buffer, l.Standings()); err != nil {
+			t.Fatal(err)
+		}
+		if buffer.String() != tt.expected {
+			t.Errorf("League for %q =\n%s\nwant\n%s", tt.description, buffer.String(), tt.expected)
+		}
+	}
+}
+
+func TestLeagueUndoAmend(t *testing.T) {
+	l, err := NewLeague(DefaultRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := leagueFrom(t, l, 1, `
+Ants;Bees;2-0
+Cats;Dogs;draw
+`)
+	leagueFrom(t, l, 2, `
+Ants;Cats;1-1
+Bees;Dogs;win
+`)
+	if got := teamsOf(l.Standings()); !reflect.DeepEqual(got, []string{"Ants", "Bees", "Cats", "Dogs"}) {
+		t.Errorf("standings = %v", got)
+	}
+
+	// Bees actually won the first game.
+	if err := l.Amend(ids[0], Result{Round: 1, Home: "Ants", Away: "Bees", Scored: true, HomeGoals: 0, AwayGoals: 3}); err != nil {
+		t.Fatal(err)
+	}
+	s := l.Standings()
+	if got := teamsOf(s); !reflect.DeepEqual(got, []string{"Bees", "Cats", "Ants", "Dogs"}) {
+		t.Errorf("standings after amending = %v", got)
+	}
+	if bees := s.Teams[0]; bees.Points != 6 || bees.GoalsFor != 3 || bees.GoalsAgainst != 0 {
+		t.Errorf("Bees after amending = %+v", bees)
+	}
+
+	// Cats and Dogs never played; once that's undone Dogs only have one game.
+	if err := l.Undo(ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	s = l.Standings()
+	if got := teamsOf(s); !reflect.DeepEqual(got, []string{"Bees", "Ants", "Cats", "Dogs"}) {
+		t.Errorf("standings after undoing = %v", got)
+	}
+	if dogs := s.Teams[3]; dogs.Played != 1 || dogs.Points != 0 {
+		t.Errorf("Dogs after undoing = %+v", dogs)
+	}
+
+	if err := l.Undo(ids[1]); err == nil {
+		t.Error("undoing a result twice should fail")
+	}
+	if err := l.Amend(ids[1], Result{Home: "Cats", Away: "Dogs"}); err == nil {
+		t.Error("amending an undone result should fail")
+	}
+	if err := l.Amend(ids[0], Result{Home: "Ants", Away: "Ants"}); err == nil {
+		t.Error("amending to a bad result should fail")
+	}
+	if r, ok := l.Result(ids[0]); !ok || r.AwayGoals != 3 {
+		t.Errorf("Result(%d) = %+v, %v after a failed amend", ids[0], r, ok)
+	}
+}
+
+func TestLeagueTeamLeavesTable(t *testing.T) {
+	l, _ := NewLeague(DefaultRules)
+	ids := leagueFrom(t, l, 1, "Ants;Bees;win\nAnts;Cats;loss")
+	if err := l.Undo(ids[0]); err != nil {
+		t.Fatal(err)
+	}
+	if got := teamsOf(l.Standings()); !reflect.DeepEqual(got, []string{"Cats", "Ants"}) {
+		t.Errorf("standings = %v, want Bees gone", got)
+	}
+}
+
+func TestLeagueReplay(t *testing.T) {
+	l, _ := NewLeague(DefaultRules)
+	ids := leagueFrom(t, l, 1, "Ants;Bees;win\nCats;Dogs;loss")
+	leagueFrom(t, l, 2, "Ants;Dogs;draw\nBees;Cats;1-0")
+	if err := l.Amend(ids[1], Result{Round: 1, Home: "Cats", Away: "Dogs", Outcome: Win}); err != nil {
+		t.Fatal(err)
+	}
+	leagueFrom(t, l, 3, "Ants;Cats;loss")
+	if err := l.Undo(ids[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	events := l.Events()
+	var kinds []string
+	for i, e := range events {
+		if e.Seq != i+1 {
+			t.Errorf("event %d has Seq %d", i+1, e.Seq)
+		}
+		kinds = append(kinds, e.Kind.String())
+	}
+	if got := strings.Join(kinds, ","); got != "recorded,recorded,recorded,recorded,amended,recorded,undone" {
+		t.Errorf("event kinds = %s", got)
+	}
+
+	full, err := Replay(DefaultRules, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(full.Standings(), l.Standings()) {
+		t.Errorf("replayed standings = %+v, want %+v", full.Standings(), l.Standings())
+	}
+
+	// As it was before the amendment, Dogs had won.
+	before, err := Replay(DefaultRules, events[:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := teamsOf(before.Standings()); !reflect.DeepEqual(got, []string{"Ants", "Dogs", "Bees", "Cats"}) {
+		t.Errorf("standings after 4 events = %v", got)
+	}
+
+	// As of round 2, counting the amendment and the undo.
+	if got := teamsOf(l.StandingsAsOfRound(2)); !reflect.DeepEqual(got, []string{"Bees", "Cats", "Ants", "Dogs"}) {
+		t.Errorf("standings as of round 2 = %v", got)
+	}
+	if got := l.StandingsAsOfRound(0); len(got.Teams) != 0 {
+		t.Errorf("standings as of round 0 = %+v", got)
+	}
+
+	// Logs that don't hang together are refused.
+	bad := append([]Event(nil), events...)
+	bad[6].ID = 42
+	if _, err := Replay(DefaultRules, bad); err == nil || !strings.Contains(err.Error(), "event 7") {
+		t.Errorf("Replay of an undo of a missing result: error = %v", err)
+	}
+	if _, err := Replay(DefaultRules, events[1:]); err == nil {
+		t.Error("Replay of a log missing its start should fail")
+	}
+}
+
+func TestLeagueBadResults(t *testing.T) {
+	if _, err := NewLeague(Rules{TieBreakers: []TieBreaker{-1}}); err == nil {
+		t.Error("NewLeague with a bad tie-breaker should fail")
+	}
+	l, _ := NewLeague(DefaultRules)
+	for _, r := range []Result{
+		{Home: "Ants"},
+		{Home: "Ants", Away: "Ants"},
+		{Home: "Ants", Away: "Bees", Outcome: 7},
+		{Home: "Ants", Away: "Bees", Scored: true, HomeGoals: -1},
+		{Round: -1, Home: "Ants", Away: "Bees"},
+	} {
+		if _, err := l.Record(r); err == nil {
+			t.Errorf("Record(%+v) should have failed", r)
+		}
+	}
+	if len(l.Events()) != 0 {
+		t.Errorf("failed records were logged: %+v", l.Events())
+	}
+	if _, err := ParseResult("Ants;Bees;"); err == nil {
+		t.Error("ParseResult of a fixture with no result should fail")
+	}
+}
+
+func teamsOf(s Standings) []string {
+	var teams []string
+	for _, t := range s.Teams {
+		teams = append(teams, t.Team)
+	}
+	return teams
+}
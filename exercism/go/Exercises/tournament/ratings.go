@@ -0,0 +1,308 @@
+package tournament
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Rating is how strong a team is reckoned to be after its games so far.
+type Rating struct {
+	Team   string
+	Played int
+	Rating float64
+	// Deviation and Volatility are only worked out by Glicko2. Deviation is
+	// how unsure the rating is, in the same units, and Volatility how much the
+	// team's form has been swinging about.
+	Deviation  float64
+	Volatility float64
+}
+
+// RatingSystem is a way of rating teams from their results: Elo or Glicko2.
+type RatingSystem interface {
+	// start returns the rating of a team before its first game.
+	start(team string) Rating
+	// update returns both teams' ratings after a game between them, given
+	// the home team's score of 1 for a win, 0.5 for a draw or 0 for a loss.
+	update(home, away Rating, score float64) (Rating, Rating)
+	// expected returns the score a is expected to get against b.
+	expected(a, b Rating) float64
+	// deviations reports whether ratings from the system have a deviation.
+	deviations() bool
+	// validate reports settings the system can't rate with.
+	validate() error
+}
+
+// Elo is the Elo rating system, where after each game a team gains K times how
+// much better it did than expected, and the other team loses the same.
+type Elo struct {
+	K       float64 // the most a rating can change in one game
+	Initial float64 // every team's rating before its first game
+}
+
+// DefaultElo is Elo as chess federations commonly use it, with K = 32 and
+// every team starting at 1500.
+var DefaultElo = Elo{K: 32, Initial: 1500}
+
+func (e Elo) start(team string) Rating {
+	return Rating{Team: team, Rating: e.Initial}
+}
+
+func (e Elo) update(home, away Rating, score float64) (Rating, Rating) {
+	change := e.K * (score - e.expected(home, away))
+	home.Rating += change
+	away.Rating -= change
+	return home, away
+}
+
+func (Elo) expected(a, b Rating) float64 {
+	return 1 / (1 + math.Pow(10, (b.Rating-a.Rating)/400))
+}
+
+func (Elo) deviations() bool { return false }
+
+func (e Elo) validate() error {
+	if !(e.K > 0) || math.IsInf(e.K, 1) {
+		return fmt.Errorf("tournament: Elo K is %v, want a positive number", e.K)
+	}
+	return checkFinite("Elo", "Initial", e.Initial)
+}
+
+// Glicko2 is Mark Glickman's Glicko-2 rating system, which keeps track of how
+// reliable each rating is as well as the rating itself. A team that has played
+// little, or whose results keep surprising, has its rating moved further by
+// each game. Every game is treated as a rating period of its own, so ratings
+// change in match order just as with Elo.
+type Glicko2 struct {
+	Initial    float64 // every team's rating before its first game
+	Deviation  float64 // every team's rating deviation before its first game
+	Volatility float64 // every team's volatility before its first game
+	// Tau limits how fast volatility can change. Glickman suggests between 0.3
+	// and 1.2; smaller values suit games where upsets are rare.
+	Tau float64
+}
+
+// DefaultGlicko2 is Glicko2 with the starting values Glickman recommends.
+var DefaultGlicko2 = Glicko2{Initial: 1500, Deviation: 350, Volatility: 0.06, Tau: 0.5}
+
+// glickoScale converts between the Glicko rating scale and the one Glicko-2
+// does its sums on.
+const glickoScale = 173.7178
+
+func (g Glicko2) start(team string) Rating {
+	return Rating{Team: team, Rating: g.Initial, Deviation: g.Deviation, Volatility: g.Volatility}
+}
+
+func (g Glicko2) update(home, away Rating, score float64) (Rating, Rating) {
+	newHome := g.rate(home, []Rating{away}, []float64{score})
+	newAway := g.rate(away, []Rating{home}, []float64{1 - score})
+	return newHome, newAway
+}
+
+func (Glicko2) expected(a, b Rating) float64 {
+	// Both teams' uncertainty flattens the expectation towards a coin toss.
+	phi := math.Hypot(a.Deviation, b.Deviation) / glickoScale
+	return glickoE(a.Rating/glickoScale, b.Rating/glickoScale, phi)
+}
+
+func (Glicko2) deviations() bool { return true }
+
+func (g Glicko2) validate() error {
+	for _, v := range []struct {
+		name  string
+		value float64
+	}{{"Deviation", g.Deviation}, {"Volatility", g.Volatility}, {"Tau", g.Tau}} {
+		if !(v.value > 0) || math.IsInf(v.value, 1) {
+			return fmt.Errorf("tournament: Glicko2 %s is %v, want a positive number", v.name, v.value)
+		}
+	}
+	return checkFinite("Glicko2", "Initial", g.Initial)
+}
+
+// checkFinite reports a setting that is infinite or not a number.
+func checkFinite(system, name string, value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("tournament: %s %s is %v, want a number", system, name, value)
+	}
+	return nil
+}
+
+// rate applies one Glicko-2 rating period to r, in which it played opponents
+// with the given scores. This follows the steps in Glickman's "Example of the
+// Glicko-2 system".
+func (g Glicko2) rate(r Rating, opponents []Rating, scores []float64) Rating {
+	mu := (r.Rating - g.Initial) / glickoScale
+	phi := r.Deviation / glickoScale
+	sigma := r.Volatility
+
+	// Step 3 and 4: the estimated variance of the rating from the games
+	// alone, and the estimated improvement.
+	var vInv, gain float64
+	for i, o := range opponents {
+		muJ := (o.Rating - g.Initial) / glickoScale
+		phiJ := o.Deviation / glickoScale
+		gJ := glickoG(phiJ)
+		e := glickoE(mu, muJ, phiJ)
+		vInv += gJ * gJ * e * (1 - e)
+		gain += gJ * (scores[i] - e)
+	}
+	v := 1 / vInv
+	delta := v * gain
+
+	// Step 5: find the new volatility by the Illinois algorithm.
+	a := math.Log(sigma * sigma)
+	tau2 := g.Tau * g.Tau
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/tau2
+	}
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*g.Tau) < 0 {
+			k++
+		}
+		B = a - k*g.Tau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > 1e-6 {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	sigma = math.Exp(A / 2)
+
+	// Step 6 to 8: the new deviation and rating.
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+vInv)
+	mu += phi * phi * gain
+
+	r.Rating = mu*glickoScale + g.Initial
+	r.Deviation = phi * glickoScale
+	r.Volatility = sigma
+	return r
+}
+
+// glickoG weighs an opponent's rating by how unsure it is.
+func glickoG(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// glickoE is the expected score of a team rated mu against one rated muJ with
+// deviation phiJ.
+func glickoE(mu, muJ, phiJ float64) float64 {
+	return 1 / (1 + math.Exp(-glickoG(phiJ)*(mu-muJ)))
+}
+
+// Ratings are teams' ratings after a run of games, best team first.
+type Ratings struct {
+	Teams  []Rating
+	system RatingSystem
+}
+
+// ComputeRatings reads games in the same way as Tally, and rates the teams
+// with system, taking the games in the order they are given. It fails if
+// system is nil or its settings can't be rated with, such as a K, Deviation,
+// Volatility or Tau that isn't above zero.
+func ComputeRatings(reader io.Reader, system RatingSystem) (Ratings, error) {
+	if system == nil {
+		return Ratings{}, errors.New("tournament: no rating system")
+	}
+	if err := system.validate(); err != nil {
+		return Ratings{}, err
+	}
+	entries, err := readEntries(reader)
+	if err != nil {
+		return Ratings{}, err
+	}
+	byTeam := map[string]Rating{}
+	rating := func(team string) Rating {
+		if r, ok := byTeam[team]; ok {
+			return r
+		}
+		return system.start(team)
+	}
+	for _, entry := range entries {
+		var score float64
+		switch entry.outcomes[0] {
+		case Win:
+			score = 1
+		case Draw:
+			score = 0.5
+		}
+		home, away := system.update(rating(entry.teams[0]), rating(entry.teams[1]), score)
+		home.Played++
+		away.Played++
+		byTeam[home.Team], byTeam[away.Team] = home, away
+	}
+
+	ratings := Ratings{Teams: make([]Rating, 0, len(byTeam)), system: system}
+	for _, r := range byTeam {
+		ratings.Teams = append(ratings.Teams, r)
+	}
+	sort.Slice(ratings.Teams, func(i, j int) bool {
+		a, b := ratings.Teams[i], ratings.Teams[j]
+		if a.Rating != b.Rating {
+			return a.Rating > b.Rating
+		}
+		return a.Team < b.Team
+	})
+	return ratings, nil
+}
+
+// Rating returns the named team's rating. A team that hasn't played has the
+// rating the system starts everyone on.
+func (r Ratings) Rating(team string) Rating {
+	for _, t := range r.Teams {
+		if t.Team == team {
+			return t
+		}
+	}
+	return r.ratingSystem().start(team)
+}
+
+// WinProbability returns the chance of home beating away, counting a draw as
+// half a win, going by their ratings.
+func (r Ratings) WinProbability(home, away string) float64 {
+	return r.ratingSystem().expected(r.Rating(home), r.Rating(away))
+}
+
+// ratingSystem returns the system r was worked out with. The zero Ratings,
+// which wasn't, is taken to be DefaultElo before any games.
+func (r Ratings) ratingSystem() RatingSystem {
+	if r.system == nil {
+		return DefaultElo
+	}
+	return r.system
+}
+
+// Render writes the ratings as a fixed-width table like the one Tally writes.
+func (r Ratings) Render(writer io.Writer) error {
+	w := bufio.NewWriter(writer)
+	deviations := r.system != nil && r.system.deviations()
+	if deviations {
+		fmt.Fprintf(w, "%-31s| %2s | %6s | %5s\n", "Team", "MP", "Rating", "RD")
+	} else {
+		fmt.Fprintf(w, "%-31s| %2s | %6s\n", "Team", "MP", "Rating")
+	}
+	for _, t := range r.Teams {
+		if deviations {
+			fmt.Fprintf(w, "%-31s| %2d | %6.0f | %5.0f\n", t.Team, t.Played, t.Rating, t.Deviation)
+			continue
+		}
+		fmt.Fprintf(w, "%-31s| %2d | %6.0f\n", t.Team, t.Played, t.Rating)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,154 @@
+package tournament
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGlicko2Example(t *testing.T) {
+	// The worked example from Glickman's "Example of the Glicko-2 system".
+	g := Glicko2{Initial: 1500, Tau: 0.5}
+	player := Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	got := g.rate(player, []Rating{
+		{Rating: 1400, Deviation: 30},
+		{Rating: 1550, Deviation: 100},
+		{Rating: 1700, Deviation: 300},
+	}, []float64{1, 0, 0})
+	if math.Abs(got.Rating-1464.06) > 0.01 || math.Abs(got.Deviation-151.52) > 0.01 ||
+		math.Abs(got.Volatility-0.05999) > 0.00001 {
+		t.Errorf("rate = %+v, want 1464.06, 151.52, 0.05999", got)
+	}
+}
+
+func TestElo(t *testing.T) {
+	ratings, err := ComputeRatings(strings.NewReader("Ants;Bees;win\nBees;Cats;draw\n"), DefaultElo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ants gain 16 as the teams were level. Bees at 1484 then draw with
+	// Cats at 1500, so take back 32 * (0.5 - 0.477).
+	bees := 1484 + 32*(0.5-1/(1+math.Pow(10, 16.0/400)))
+	for _, want := range []Rating{
+		{Team: "Ants", Played: 1, Rating: 1516},
+		{Team: "Cats", Played: 1, Rating: 1500 - (bees - 1484)},
+		{Team: "Bees", Played: 2, Rating: bees},
+	} {
+		got := ratings.Rating(want.Team)
+		if got.Played != want.Played || math.Abs(got.Rating-want.Rating) > 1e-9 {
+			t.Errorf("Rating(%q) = %+v, want %+v", want.Team, got, want)
+		}
+	}
+	if got := ratings.Teams[0].Team; got != "Ants" {
+		t.Errorf("top rated team is %s, want Ants", got)
+	}
+
+	// Twice the K-factor moves ratings twice as far after one game.
+	ratings, err = ComputeRatings(strings.NewReader("Ants;Bees;1-0"), Elo{K: 64, Initial: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ratings.Rating("Ants").Rating; got != 1032 {
+		t.Errorf("Ants with K = 64 rated %v, want 1032", got)
+	}
+	if got := ratings.Rating("Nobody").Rating; got != 1000 {
+		t.Errorf("unplayed team rated %v, want 1000", got)
+	}
+}
+
+func TestWinProbability(t *testing.T) {
+	input := `
+Ants;Bees;win
+Ants;Cats;win
+Bees;Cats;win
+Ants;Bees;win
+`
+	for _, system := range []RatingSystem{DefaultElo, DefaultGlicko2} {
+		ratings, err := ComputeRatings(strings.NewReader(input), system)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := order(renderRatings(t, ratings)); strings.Join(got, ",") != "Ants,Bees,Cats" {
+			t.Errorf("%T order = %v", system, got)
+		}
+		p := ratings.WinProbability("Ants", "Cats")
+		q := ratings.WinProbability("Cats", "Ants")
+		if p <= 0.5 || p >= 1 || math.Abs(p+q-1) > 1e-9 {
+			t.Errorf("%T: Ants beat Cats with probability %v, Cats beat Ants %v", system, p, q)
+		}
+		if got := ratings.WinProbability("Bees", "Bees"); math.Abs(got-0.5) > 1e-9 {
+			t.Errorf("%T: Bees against themselves %v, want 0.5", system, got)
+		}
+	}
+}
+
+func TestGlicko2Deviation(t *testing.T) {
+	ratings, err := ComputeRatings(strings.NewReader("Ants;Bees;win\nAnts;Cats;draw\nAnts;Bees;win\n"), DefaultGlicko2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ants, cats := ratings.Rating("Ants"), ratings.Rating("Cats")
+	if !(ants.Deviation < cats.Deviation && cats.Deviation < 350) {
+		t.Errorf("deviations Ants %v, Cats %v: more games should mean less doubt", ants.Deviation, cats.Deviation)
+	}
+	table := renderRatings(t, ratings)
+	if !strings.HasPrefix(table, "Team                           | MP | Rating |    RD\n") {
+		t.Errorf("Glicko2 table:\n%s", table)
+	}
+}
+
+func TestRatingsRender(t *testing.T) {
+	ratings, err := ComputeRatings(strings.NewReader("Ants;Bees;loss"), DefaultElo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `
+Team                           | MP | Rating
+Bees                           |  1 |   1516
+Ants                           |  1 |   1484
+`[1:]
+	if got := renderRatings(t, ratings); got != want {
+		t.Errorf("Render =\n%s\nwant\n%s", got, want)
+	}
+	if _, err := ComputeRatings(strings.NewReader("Ants;Bees;dra"), DefaultElo); err == nil {
+		t.Error("ComputeRatings of bad input should fail")
+	}
+}
+
+func TestRatingSystemSettings(t *testing.T) {
+	for _, system := range []RatingSystem{
+		nil,
+		Elo{},
+		Elo{K: -32, Initial: 1500},
+		Elo{K: 32, Initial: math.NaN()},
+		Elo{K: math.Inf(1), Initial: 1500},
+		Glicko2{},
+		Glicko2{Initial: 1500, Deviation: 350, Volatility: 0.06},
+		Glicko2{Initial: 1500, Deviation: 0, Volatility: 0.06, Tau: 0.5},
+		Glicko2{Initial: 1500, Deviation: 350, Volatility: -1, Tau: 0.5},
+		Glicko2{Initial: math.Inf(-1), Deviation: 350, Volatility: 0.06, Tau: 0.5},
+	} {
+		if _, err := ComputeRatings(strings.NewReader("Ants;Bees;win"), system); err == nil {
+			t.Errorf("ComputeRatings with %#v should have failed", system)
+		}
+	}
+
+	// The zero Ratings knows no teams, and so has no favourite.
+	var zero Ratings
+	if got := zero.WinProbability("Ants", "Bees"); got != 0.5 {
+		t.Errorf("zero Ratings WinProbability = %v, want 0.5", got)
+	}
+	if got := zero.Rating("Ants"); got.Team != "Ants" || got.Played != 0 {
+		t.Errorf("zero Ratings Rating = %+v", got)
+	}
+}
+
+func renderRatings(t *testing.T, r Ratings) string {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := r.Render(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.String()
+}
@@ -0,0 +1,236 @@
+package tournament
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Standing is one team's line in the standings.
+type Standing struct {
+	Position       int // 1 for the top team, counting down the table
+	Team           string
+	Played         int
+	Wins           int
+	Draws          int
+	Losses         int
+	GoalsFor       int
+	GoalsAgainst   int
+	GoalDifference int
+	Points         int
+}
+
+// Standings is the league table worked out from a set of games, best team
+// first.
+type Standings struct {
+	Teams []Standing
+	// Scored is set when at least one game came with a score, so the goal
+	// columns mean something. Renderers leave them out otherwise.
+	Scored bool
+}
+
+// newStandings turns the ranked results into Standings.
+func newStandings(results []teamResult, scored bool) Standings {
+	s := Standings{Teams: make([]Standing, len(results)), Scored: scored}
+	for i, r := range results {
+		s.Teams[i] = Standing{
+			Position:       i + 1,
+			Team:           r.team,
+			Played:         r.played,
+			Wins:           r.wins,
+			Draws:          r.draws,
+			Losses:         r.losses,
+			GoalsFor:       r.goalsFor,
+			GoalsAgainst:   r.goalsAgainst,
+			GoalDifference: r.goalDifference(),
+			Points:         r.points,
+		}
+	}
+	return s
+}
+
+// Renderer writes standings out in some format.
+type Renderer interface {
+	Render(w io.Writer, s Standings) error
+}
+
+// RendererFunc lets an ordinary function be used as a Renderer.
+type RendererFunc func(w io.Writer, s Standings) error
+
+// Render calls f(w, s).
+func (f RendererFunc) Render(w io.Writer, s Standings) error {
+	return f(w, s)
+}
+
+// headings returns the short column names, with or without the goal columns.
+func (s Standings) headings() []string {
+	if s.Scored {
+		return []string{"MP", "W", "D", "L", "GF", "GA", "GD", "P"}
+	}
+	return []string{"MP", "W", "D", "L", "P"}
+}
+
+// numbers returns the values to go under headings for one team.
+func (s Standings) numbers(t Standing) []int {
+	if s.Scored {
+		return []int{t.Played, t.Wins, t.Draws, t.Losses, t.GoalsFor, t.GoalsAgainst, t.GoalDifference, t.Points}
+	}
+	return []int{t.Played, t.Wins, t.Draws, t.Losses, t.Points}
+}
+
+// TextRenderer writes the fixed-width table that Tally produces.
+type TextRenderer struct{}
+
+// Render writes s as a fixed-width table.
+func (TextRenderer) Render(writer io.Writer, s Standings) error {
+	w := bufio.NewWriter(writer)
+	if s.Scored {
+		fmt.Fprintf(w, "%-31s| %2s | %2s | %2s | %2s | %2s | %2s | %3s | %2s\n",
+			"Team", "MP", "W", "D", "L", "GF", "GA", "GD", "P")
+	} else {
+		fmt.Fprintf(w, "%-31s| %2s | %2s | %2s | %2s | %2s\n", "Team", "MP", "W", "D", "L", "P")
+	}
+	for _, t := range s.Teams {
+		if s.Scored {
+			fmt.Fprintf(w, "%-31s| %2d | %2d | %2d | %2d | %2d | %2d | %3s | %2d\n",
+				t.Team, t.Played, t.Wins, t.Draws, t.Losses,
+				t.GoalsFor, t.GoalsAgainst, signed(t.GoalDifference), t.Points)
+			continue
+		}
+		fmt.Fprintf(w, "%-31s| %2d | %2d | %2d | %2d | %2d\n",
+			t.Team, t.Played, t.Wins, t.Draws, t.Losses, t.Points)
+	}
+	return w.Flush()
+}
+
+// signed formats n with a plus sign if it is above zero, as goal differences
+// usually are.
+func signed(n int) string {
+	if n > 0 {
+		return "+" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// JSONRenderer writes the standings as a JSON array with one object per team.
+// Goal fields only appear when the standings are Scored.
+type JSONRenderer struct {
+	// Indent, if not empty, pretty-prints the output with this indent.
+	Indent string
+}
+
+type jsonStanding struct {
+	Position       int    `json:"position"`
+	Team           string `json:"team"`
+	Played         int    `json:"played"`
+	Wins           int    `json:"wins"`
+	Draws          int    `json:"draws"`
+	Losses         int    `json:"losses"`
+	GoalsFor       *int   `json:"goals_for,omitempty"`
+	GoalsAgainst   *int   `json:"goals_against,omitempty"`
+	GoalDifference *int   `json:"goal_difference,omitempty"`
+	Points         int    `json:"points"`
+}
+
+// Render writes s as JSON.
+func (r JSONRenderer) Render(w io.Writer, s Standings) error {
+	rows := make([]jsonStanding, len(s.Teams))
+	for i := range s.Teams {
+		t := &s.Teams[i]
+		rows[i] = jsonStanding{
+			Position: t.Position,
+			Team:     t.Team,
+			Played:   t.Played,
+			Wins:     t.Wins,
+			Draws:    t.Draws,
+			Losses:   t.Losses,
+			Points:   t.Points,
+		}
+		if s.Scored {
+			rows[i].GoalsFor = &t.GoalsFor
+			rows[i].GoalsAgainst = &t.GoalsAgainst
+			rows[i].GoalDifference = &t.GoalDifference
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", r.Indent)
+	return enc.Encode(rows)
+}
+
+// CSVRenderer writes the standings as CSV with a header row.
+type CSVRenderer struct{}
+
+// Render writes s as CSV.
+func (CSVRenderer) Render(w io.Writer, s Standings) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"Pos", "Team"}, s.headings()...)); err != nil {
+		return err
+	}
+	for _, t := range s.Teams {
+		record := []string{strconv.Itoa(t.Position), t.Team}
+		for _, n := range s.numbers(t) {
+			record = append(record, strconv.Itoa(n))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// MarkdownRenderer writes the standings as a GitHub-flavoured Markdown table.
+type MarkdownRenderer struct{}
+
+// Render writes s as a Markdown table.
+func (MarkdownRenderer) Render(writer io.Writer, s Standings) error {
+	w := bufio.NewWriter(writer)
+	headings := s.headings()
+	fmt.Fprintf(w, "| Pos | Team | %s |\n", strings.Join(headings, " | "))
+	fmt.Fprintf(w, "| --: | :--- |%s\n", strings.Repeat(" --: |", len(headings)))
+	// A pipe would end the cell early, so it has to be escaped.
+	escape := strings.NewReplacer("|", `\|`)
+	for _, t := range s.Teams {
+		fmt.Fprintf(w, "| %d | %s |", t.Position, escape.Replace(t.Team))
+		for _, n := range s.numbers(t) {
+			fmt.Fprintf(w, " %d |", n)
+		}
+		fmt.Fprintln(w)
+	}
+	return w.Flush()
+}
+
+// HTMLRenderer writes the standings as an HTML table.
+type HTMLRenderer struct {
+	// Class, if not empty, is set as the table's class attribute.
+	Class string
+}
+
+// Render writes s as an HTML table.
+func (r HTMLRenderer) Render(writer io.Writer, s Standings) error {
+	w := bufio.NewWriter(writer)
+	if r.Class != "" {
+		fmt.Fprintf(w, "<table class=\"%s\">\n", html.EscapeString(r.Class))
+	} else {
+		fmt.Fprintln(w, "<table>")
+	}
+	fmt.Fprint(w, "<thead>\n<tr><th>Pos</th><th>Team</th>")
+	for _, h := range s.headings() {
+		fmt.Fprintf(w, "<th>%s</th>", h)
+	}
+	fmt.Fprint(w, "</tr>\n</thead>\n<tbody>\n")
+	for _, t := range s.Teams {
+		fmt.Fprintf(w, "<tr><td>%d</td><td>%s</td>", t.Position, html.EscapeString(t.Team))
+		for _, n := range s.numbers(t) {
+			fmt.Fprintf(w, "<td>%d</td>", n)
+		}
+		fmt.Fprintln(w, "</tr>")
+	}
+	fmt.Fprint(w, "</tbody>\n</table>\n")
+	return w.Flush()
+}
@@ -0,0 +1,142 @@
+package tournament
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+const renderInput = `
+Ants;Bees & Co;2-0
+Cats|Dogs;Ants;1-1
+Bees & Co;Cats|Dogs;win
+`
+
+func TestComputeStandings(t *testing.T) {
+	s, err := ComputeStandings(strings.NewReader(renderInput), DefaultRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Standings{
+		Scored: true,
+		Teams: []Standing{
+			{1, "Ants", 2, 1, 1, 0, 3, 1, 2, 4},
+			{2, "Bees & Co", 2, 1, 0, 1, 0, 2, -2, 3},
+			{3, "Cats|Dogs", 2, 0, 1, 1, 1, 1, 0, 1},
+		},
+	}
+	if len(s.Teams) != len(want.Teams) || s.Scored != want.Scored {
+		t.Fatalf("ComputeStandings = %+v, want %+v", s, want)
+	}
+	for i := range want.Teams {
+		if s.Teams[i] != want.Teams[i] {
+			t.Errorf("team %d = %+v, want %+v", i, s.Teams[i], want.Teams[i])
+		}
+	}
+
+	if _, err := ComputeStandings(strings.NewReader("A;B;what"), DefaultRules); err == nil {
+		t.Error("ComputeStandings accepted a bad record")
+	}
+}
+
+func render(t *testing.T, input string, r Renderer) string {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := TallyWithRenderer(strings.NewReader(input), &buffer, DefaultRules, r); err != nil {
+		t.Fatalf("TallyWithRenderer(%T) returned error %v", r, err)
+	}
+	return buffer.String()
+}
+
+func TestRenderers(t *testing.T) {
+	for _, tt := range []struct {
+		renderer Renderer
+		want     string
+	}{
+		{CSVRenderer{}, `
+Pos,Team,MP,W,D,L,GF,GA,GD,P
+1,Ants,2,1,1,0,3,1,2,4
+2,Bees & Co,2,1,0,1,0,2,-2,3
+3,Cats|Dogs,2,0,1,1,1,1,0,1
+`},
+		{MarkdownRenderer{}, `
+| Pos | Team | MP | W | D | L | GF | GA | GD | P |
+| --: | :--- | --: | --: | --: | --: | --: | --: | --: | --: |
+| 1 | Ants | 2 | 1 | 1 | 0 | 3 | 1 | 2 | 4 |
+| 2 | Bees & Co | 2 | 1 | 0 | 1 | 0 | 2 | -2 | 3 |
+| 3 | Cats\|Dogs | 2 | 0 | 1 | 1 | 1 | 1 | 0 | 1 |
+`},
+		{HTMLRenderer{Class: "league"}, `
+<table class="league">
+<thead>
+<tr><th>Pos</th><th>Team</th><th>MP</th><th>W</th><th>D</th><th>L</th><th>GF</th><th>GA</th><th>GD</th><th>P</th></tr>
+</thead>
+<tbody>
+<tr><td>1</td><td>Ants</td><td>2</td><td>1</td><td>1</td><td>0</td><td>3</td><td>1</td><td>2</td><td>4</td></tr>
+<tr><td>2</td><td>Bees &amp; Co</td><td>2</td><td>1</td><td>0</td><td>1</td><td>0</td><td>2</td><td>-2</td><td>3</td></tr>
+<tr><td>3</td><td>Cats|Dogs</td><td>2</td><td>0</td><td>1</td><td>1</td><td>1</td><td>1</td><td>0</td><td>1</td></tr>
+</tbody>
+</table>
+`},
+		{TextRenderer{}, `
+Team                           | MP |  W |  D |  L | GF | GA |  GD |  P
+Ants                           |  2 |  1 |  1 |  0 |  3 |  1 |  +2 |  4
+Bees & Co                      |  2 |  1 |  0 |  1 |  0 |  2 |  -2 |  3
+Cats|Dogs                      |  2 |  0 |  1 |  1 |  1 |  1 |   0 |  1
+`},
+	} {
+		if got := render(t, renderInput, tt.renderer); got != tt.want[1:] {
+			t.Errorf("%T output:\n%s\nwant\n%s", tt.renderer, got, tt.want[1:])
+		}
+	}
+}
+
+func TestJSONRenderer(t *testing.T) {
+	var rows []map[string]interface{}
+	if err := json.Unmarshal([]byte(render(t, renderInput, JSONRenderer{})), &rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[1]["team"] != "Bees & Co" || rows[1]["goal_difference"] != -2.0 || rows[1]["position"] != 2.0 {
+		t.Errorf("JSON rows = %v", rows)
+	}
+
+	got := render(t, "A;B;win", JSONRenderer{Indent: " "})
+	want := `[
+ {
+  "position": 1,
+  "team": "A",
+  "played": 1,
+  "wins": 1,
+  "draws": 0,
+  "losses": 0,
+  "points": 3
+ },
+ {
+  "position": 2,
+  "team": "B",
+  "played": 1,
+  "wins": 0,
+  "draws": 0,
+  "losses": 1,
+  "points": 0
+ }
+]
+`
+	if got != want {
+		t.Errorf("unscored JSON =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRendererFunc(t *testing.T) {
+	names := RendererFunc(func(w io.Writer, s Standings) error {
+		for _, team := range s.Teams {
+			io.WriteString(w, team.Team+"\n")
+		}
+		return nil
+	})
+	if got := render(t, renderInput, names); got != "Ants\nBees & Co\nCats|Dogs\n" {
+		t.Errorf("RendererFunc output = %q", got)
+	}
+}
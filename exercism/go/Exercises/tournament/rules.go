@@ -0,0 +1,185 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TieBreaker is one way of putting teams in order. Rules apply a list of them
+// in turn, each only deciding between teams the earlier ones left level.
+type TieBreaker int
+
+const (
+	// ByPoints puts teams with more points first.
+	ByPoints TieBreaker = iota
+	// ByWins puts teams with more wins first.
+	ByWins
+	// ByHeadToHead puts first the teams that took more points from the games
+	// played among the teams still level.
+	ByHeadToHead
+	// ByGoalDifference puts teams that scored more goals than they conceded
+	// first. Only games given with a score count towards it.
+	ByGoalDifference
+	// ByGoalsFor puts teams that scored more goals first.
+	ByGoalsFor
+	// ByName puts teams in alphabetical order.
+	ByName
+)
+
+func (b TieBreaker) String() string {
+	switch b {
+	case ByPoints:
+		return "points"
+	case ByWins:
+		return "wins"
+	case ByHeadToHead:
+		return "head-to-head"
+	case ByGoalDifference:
+		return "goal difference"
+	case ByGoalsFor:
+		return "goals for"
+	case ByName:
+		return "name"
+	}
+	return fmt.Sprintf("TieBreaker(%d)", int(b))
+}
+
+// Game is one team's side of a game, as handed to Rules.Bonus. The goals are
+// only filled in when Scored is set.
+type Game struct {
+	Team, Opponent string
+	Outcome        Outcome
+	Scored         bool
+	GoalsFor       int
+	GoalsAgainst   int
+}
+
+// Rules decide how many points a team earns from a game, and how the standings
+// are ordered.
+type Rules struct {
+	// Win, Draw and Loss are the points for each outcome.
+	Win, Draw, Loss int
+
+	// Bonus, if set, is called for each team in each game, and whatever it
+	// returns is added to that team's points. It can be negative.
+	Bonus func(Game) int
+
+	// TieBreakers order the standings, the first one deciding most places.
+	// Teams level on all of them, or all teams if there are none, fall back to
+	// points and then name.
+	TieBreakers []TieBreaker
+}
+
+// DefaultRules give 3 points for a win and 1 for a draw, and order teams by
+// points and then name.
+var DefaultRules = Rules{
+	Win:         3,
+	Draw:        1,
+	Loss:        0,
+	TieBreakers: []TieBreaker{ByPoints, ByName},
+}
+
+// validate checks that every tie-breaker is one this package knows about.
+func (r Rules) validate() error {
+	for _, b := range r.TieBreakers {
+		if b < ByPoints || b > ByName {
+			return fmt.Errorf("tournament: unknown tie-breaker %v", b)
+		}
+	}
+	return nil
+}
+
+// points returns what the team on the given side of entry earned from it.
+func (r Rules) points(entry inputEntry, side int) int {
+	var p int
+	switch entry.outcomes[side] {
+	case Win:
+		p = r.Win
+	case Draw:
+		p = r.Draw
+	case Loss:
+		p = r.Loss
+	}
+	if r.Bonus != nil {
+		p += r.Bonus(Game{
+			Team:         entry.teams[side],
+			Opponent:     entry.teams[1-side],
+			Outcome:      entry.outcomes[side],
+			Scored:       entry.scored,
+			GoalsFor:     entry.goals[side],
+			GoalsAgainst: entry.goals[1-side],
+		})
+	}
+	return p
+}
+
+// rank sorts the standings in place using the tie-breakers.
+func (r Rules) rank(results []teamResult, entries []inputEntry) {
+	breakers := append([]TieBreaker{}, r.TieBreakers...)
+	breakers = append(breakers, ByPoints, ByName)
+	r.rankGroup(results, breakers, entries)
+}
+
+// rankGroup orders a group of teams by the first tie-breaker, then hands each
+// run of teams still level on to the next one.
+func (r Rules) rankGroup(group []teamResult, breakers []TieBreaker, entries []inputEntry) {
+	if len(group) < 2 || len(breakers) == 0 {
+		return
+	}
+	if breakers[0] == ByName {
+		// Names are unique, so nothing can be left level after this.
+		sort.Slice(group, func(i, j int) bool { return group[i].team < group[j].team })
+		return
+	}
+
+	key := r.keys(breakers[0], group, entries)
+	sort.SliceStable(group, func(i, j int) bool { return key[group[i].team] > key[group[j].team] })
+	for i := 0; i < len(group); {
+		j := i + 1
+		for j < len(group) && key[group[j].team] == key[group[i].team] {
+			j++
+		}
+		r.rankGroup(group[i:j], breakers[1:], entries)
+		i = j
+	}
+}
+
+// keys scores each team in the group by a tie-breaker, where a higher score
+// ranks higher.
+func (r Rules) keys(b TieBreaker, group []teamResult, entries []inputEntry) map[string]int {
+	key := make(map[string]int, len(group))
+	switch b {
+	case ByPoints:
+		for _, t := range group {
+			key[t.team] = t.points
+		}
+	case ByWins:
+		for _, t := range group {
+			key[t.team] = t.wins
+		}
+	case ByGoalDifference:
+		for _, t := range group {
+			key[t.team] = t.goalDifference()
+		}
+	case ByGoalsFor:
+		for _, t := range group {
+			key[t.team] = t.goalsFor
+		}
+	case ByHeadToHead:
+		// Make a mini-league out of only the games between teams in the group.
+		for _, t := range group {
+			key[t.team] = 0
+		}
+		for _, entry := range entries {
+			_, home := key[entry.teams[0]]
+			_, away := key[entry.teams[1]]
+			if !home || !away {
+				continue
+			}
+			for side, team := range entry.teams {
+				key[team] += r.points(entry, side)
+			}
+		}
+	}
+	return key
+}
@@ -0,0 +1,123 @@
+package tournament
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func tallyRules(t *testing.T, input string, rules Rules) string {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := TallyWithRules(strings.NewReader(input), &buffer, rules); err != nil {
+		t.Fatalf("TallyWithRules(%q) returned error %v", input, err)
+	}
+	return buffer.String()
+}
+
+func TestDefaultRulesMatchTally(t *testing.T) {
+	for _, tt := range happyTestCases {
+		if got := tallyRules(t, tt.input, DefaultRules); got != tt.expected {
+			t.Errorf("TallyWithRules(%q, DefaultRules) =\n%s\nwant\n%s", tt.description, got, tt.expected)
+		}
+	}
+}
+
+func TestTwoPointsForAWin(t *testing.T) {
+	input := `
+Ants;Bees;win
+Cats;Ants;draw
+Bees;Cats;draw
+`
+	got := tallyRules(t, input, Rules{Win: 2, Draw: 1})
+	want := `
+Team                           | MP |  W |  D |  L |  P
+Ants                           |  2 |  1 |  1 |  0 |  3
+Cats                           |  2 |  0 |  2 |  0 |  2
+Bees                           |  2 |  0 |  1 |  1 |  1
+`[1:]
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBonusPoints(t *testing.T) {
+	// One bonus point for any result against the Ants, and a point docked
+	// from the Cats for every game they lose.
+	rules := DefaultRules
+	rules.Bonus = func(g Game) int {
+		switch {
+		case g.Team == "Cats" && g.Outcome == Loss:
+			return -1
+		case g.Opponent == "Ants":
+			return 1
+		}
+		return 0
+	}
+	input := `
+Ants;Bees;win
+Cats;Ants;loss
+Bees;Cats;win
+`
+	got := tallyRules(t, input, rules)
+	want := `
+Team                           | MP |  W |  D |  L |  P
+Ants                           |  2 |  2 |  0 |  0 |  6
+Bees                           |  2 |  1 |  0 |  1 |  4
+Cats                           |  2 |  0 |  0 |  2 | -2
+`[1:]
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTieBreakers(t *testing.T) {
+	// Points: Ants 5, Bees 7, Cats 5, Dogs 4. Ants and Cats are level on
+	// points and wins, and Cats took 4 points from Ants to Ants' 1.
+	input := `
+Ants;Bees;draw
+Cats;Ants;win
+Bees;Cats;win
+Ants;Dogs;win
+Cats;Dogs;draw
+Dogs;Bees;win
+Bees;Dogs;win
+Ants;Cats;draw
+`
+	for _, tt := range []struct {
+		breakers []TieBreaker
+		order    []string
+	}{
+		{nil, []string{"Bees", "Ants", "Cats", "Dogs"}},
+		{[]TieBreaker{ByPoints, ByHeadToHead, ByName}, []string{"Bees", "Cats", "Ants", "Dogs"}},
+		{[]TieBreaker{ByPoints, ByWins, ByHeadToHead}, []string{"Bees", "Cats", "Ants", "Dogs"}},
+		{[]TieBreaker{ByName}, []string{"Ants", "Bees", "Cats", "Dogs"}},
+		{[]TieBreaker{ByWins, ByName}, []string{"Bees", "Ants", "Cats", "Dogs"}},
+	} {
+		rules := DefaultRules
+		rules.TieBreakers = tt.breakers
+		got := order(tallyRules(t, input, rules))
+		if strings.Join(got, ",") != strings.Join(tt.order, ",") {
+			t.Errorf("tie-breakers %v: order %v, want %v", tt.breakers, got, tt.order)
+		}
+	}
+}
+
+// order returns the team names from a standings table, top first.
+func order(table string) []string {
+	var teams []string
+	for _, line := range strings.Split(strings.TrimSpace(table), "\n")[1:] {
+		teams = append(teams, strings.TrimSpace(strings.Split(line, "|")[0]))
+	}
+	return teams
+}
+
+func TestUnknownTieBreaker(t *testing.T) {
+	rules := DefaultRules
+	rules.TieBreakers = []TieBreaker{ByPoints, TieBreaker(99)}
+	var buffer bytes.Buffer
+	err := TallyWithRules(strings.NewReader("A;B;win"), &buffer, rules)
+	if err == nil || !strings.Contains(err.Error(), "TieBreaker(99)") {
+		t.Errorf("TallyWithRules with a bad tie-breaker error = %v", err)
+	}
+}
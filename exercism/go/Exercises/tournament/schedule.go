@@ -0,0 +1,223 @@
+package tournament
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Fixture is a game to be played. In a knockout, a side that depends on an
+// earlier game has no team yet, only the number of the match whose winner will
+// take that place.
+type Fixture struct {
+	Match      int // numbered from 1 across the whole schedule
+	Home, Away string
+	// HomeFrom and AwayFrom, when not zero, are the matches whose winners will
+	// be the home and away teams.
+	HomeFrom, AwayFrom int
+}
+
+// Decided reports whether both teams in the fixture are known.
+func (f Fixture) Decided() bool {
+	return f.HomeFrom == 0 && f.AwayFrom == 0
+}
+
+// Round is a set of fixtures played at the same time.
+type Round struct {
+	Number   int // numbered from 1
+	Fixtures []Fixture
+	// Byes are the teams that have no game this round. In a knockout they go
+	// straight through to the next one.
+	Byes []string
+}
+
+// Schedule is a plan of games, round by round.
+type Schedule struct {
+	Rounds []Round
+}
+
+// RoundRobin returns a schedule in which every team plays every other team
+// once per leg. It uses the circle method: one team stays put while the rest
+// rotate around it, which keeps each team's home and away games as even as
+// they can be. With an odd number of teams, one team sits out each round.
+//
+// Each leg after the first repeats the one before with home and away swapped,
+// so legs = 2 gives the usual home and away season.
+func RoundRobin(teams []string, legs int) (Schedule, error) {
+	if err := checkTeams(teams); err != nil {
+		return Schedule{}, err
+	}
+	if legs < 1 {
+		return Schedule{}, fmt.Errorf("tournament: %d legs, want at least 1", legs)
+	}
+
+	circle := append([]string{}, teams...)
+	if len(circle)%2 == 1 {
+		circle = append(circle, "") // the team drawn against "" has a bye
+	}
+	n := len(circle)
+	var first []Round
+	for r := 0; r < n-1; r++ {
+		var round Round
+		for i := 0; i < n/2; i++ {
+			home, away := circle[i], circle[n-1-i]
+			// The fixed team would otherwise always be at home, so it
+			// swaps sides every other round.
+			if i == 0 && r%2 == 1 {
+				home, away = away, home
+			}
+			switch {
+			case home == "":
+				round.Byes = append(round.Byes, away)
+			case away == "":
+				round.Byes = append(round.Byes, home)
+			default:
+				round.Fixtures = append(round.Fixtures, Fixture{Home: home, Away: away})
+			}
+		}
+		first = append(first, round)
+		// Rotate everyone but the first team one place round the circle.
+		last := circle[n-1]
+		copy(circle[2:], circle[1:n-1])
+		circle[1] = last
+	}
+
+	var s Schedule
+	match := 0
+	for leg := 0; leg < legs; leg++ {
+		for _, r := range first {
+			round := Round{Number: len(s.Rounds) + 1, Byes: r.Byes}
+			for _, f := range r.Fixtures {
+				match++
+				f.Match = match
+				if leg%2 == 1 {
+					f.Home, f.Away = f.Away, f.Home
+				}
+				round.Fixtures = append(round.Fixtures, f)
+			}
+			s.Rounds = append(s.Rounds, round)
+		}
+	}
+	return s, nil
+}
+
+// Knockout returns a single-elimination bracket. teams are taken to be in seed
+// order, best first. When the number of teams isn't a power of two, the top
+// seeds get byes through the first round, and the bracket is laid out so the
+// top two seeds can only meet in the final.
+func Knockout(teams []string) (Schedule, error) {
+	if err := checkTeams(teams); err != nil {
+		return Schedule{}, err
+	}
+	size := 1
+	for size < len(teams) {
+		size *= 2
+	}
+
+	// A place in the bracket holds either a known team or the winner of an
+	// earlier match.
+	type slot struct {
+		team string
+		from int
+	}
+	slots := make([]slot, size)
+	for i, seed := range seedOrder(size) {
+		if seed <= len(teams) {
+			slots[i] = slot{team: teams[seed-1]}
+		}
+	}
+
+	var s Schedule
+	match := 0
+	for len(slots) > 1 {
+		round := Round{Number: len(s.Rounds) + 1}
+		next := make([]slot, 0, len(slots)/2)
+		for i := 0; i < len(slots); i += 2 {
+			home, away := slots[i], slots[i+1]
+			if away.team == "" && away.from == 0 {
+				// Byes only come up in the first round, and only ever
+				// against a real team.
+				round.Byes = append(round.Byes, home.team)
+				next = append(next, home)
+				continue
+			}
+			match++
+			round.Fixtures = append(round.Fixtures, Fixture{
+				Match: match, Home: home.team, Away: away.team,
+				HomeFrom: home.from, AwayFrom: away.from,
+			})
+			next = append(next, slot{from: match})
+		}
+		s.Rounds = append(s.Rounds, round)
+		slots = next
+	}
+	return s, nil
+}
+
+// seedOrder returns the seeds 1 to size in bracket order, so that seed 1 plays
+// seed size, the winner meets the winner of seed size/2 against size/2+1, and
+// so on. size must be a power of two.
+func seedOrder(size int) []int {
+	order := []int{1}
+	for n := 2; n <= size; n *= 2 {
+		next := make([]int, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// checkTeams makes sure there are enough teams to play, and that every name
+// will read back the same once written out for Tally.
+func checkTeams(teams []string) error {
+	if len(teams) < 2 {
+		return fmt.Errorf("tournament: %d teams, want at least 2", len(teams))
+	}
+	seen := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		switch {
+		case team == "" || team != strings.TrimSpace(team):
+			return fmt.Errorf("tournament: team name %q is blank or has spaces around it", team)
+		case strings.ContainsAny(team, ";\n") || strings.HasPrefix(team, "#"):
+			return fmt.Errorf("tournament: team name %q cannot be written as a record", team)
+		case seen[team]:
+			return fmt.Errorf("tournament: team %q listed twice", team)
+		}
+		seen[team] = true
+	}
+	return nil
+}
+
+// Encode writes the schedule in the form Tally reads, one "home;away;unplayed"
+// line per fixture. Tally skips these until "unplayed" is replaced with a
+// result, so the same file can be used to plan a competition and then to keep
+// its standings. Round headings, byes and knockout games whose teams are not
+// known yet are written as comments.
+func (s Schedule) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, round := range s.Rounds {
+		fmt.Fprintf(bw, "# Round %d\n", round.Number)
+		for _, f := range round.Fixtures {
+			if f.Decided() {
+				fmt.Fprintf(bw, "%s;%s;%s\n", f.Home, f.Away, unplayed)
+				continue
+			}
+			fmt.Fprintf(bw, "# Match %d: %s v %s\n", f.Match, side(f.Home, f.HomeFrom), side(f.Away, f.AwayFrom))
+		}
+		for _, team := range round.Byes {
+			fmt.Fprintf(bw, "# %s: bye\n", team)
+		}
+	}
+	return bw.Flush()
+}
+
+// side names one side of a fixture for Encode.
+func side(team string, from int) string {
+	if from != 0 {
+		return fmt.Sprintf("winner of match %d", from)
+	}
+	return team
+}
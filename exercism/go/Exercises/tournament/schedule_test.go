@@ -0,0 +1,221 @@
+package tournament
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func teamNames(n int) []string {
+	teams := make([]string, n)
+	for i := range teams {
+		teams[i] = fmt.Sprintf("Team %c", 'A'+i)
+	}
+	return teams
+}
+
+func TestRoundRobin(t *testing.T) {
+	for n := 2; n <= 12; n++ {
+		for _, legs := range []int{1, 2} {
+			teams := teamNames(n)
+			s, err := RoundRobin(teams, legs)
+			if err != nil {
+				t.Fatalf("RoundRobin(%d teams, %d legs) returned error %v", n, legs, err)
+			}
+			perLeg := n - 1
+			if n%2 == 1 {
+				perLeg = n
+			}
+			if len(s.Rounds) != perLeg*legs {
+				t.Errorf("%d teams, %d legs: got %d rounds, want %d", n, legs, len(s.Rounds), perLeg*legs)
+			}
+
+			met := map[[2]string]int{}
+			home := map[string]int{}
+			match := 0
+			for i, round := range s.Rounds {
+				if round.Number != i+1 {
+					t.Errorf("%d teams: round %d is numbered %d", n, i+1, round.Number)
+				}
+				playing := map[string]bool{}
+				for _, f := range round.Fixtures {
+					match++
+					if f.Match != match {
+						t.Errorf("%d teams: fixture %v numbered %d, want %d", n, f, f.Match, match)
+					}
+					if playing[f.Home] || playing[f.Away] {
+						t.Errorf("%d teams: a team plays twice in round %d", n, round.Number)
+					}
+					playing[f.Home], playing[f.Away] = true, true
+					met[[2]string{f.Home, f.Away}]++
+					home[f.Home]++
+				}
+				for _, team := range round.Byes {
+					playing[team] = true
+				}
+				if len(playing) != n || len(round.Byes) != n%2 {
+					t.Errorf("%d teams: round %d has %d teams and %d byes", n, round.Number, len(playing), len(round.Byes))
+				}
+			}
+
+			// Every pair meets once per leg, alternating who is at home.
+			for i, a := range teams {
+				for _, b := range teams[i+1:] {
+					ab, ba := met[[2]string{a, b}], met[[2]string{b, a}]
+					if ab+ba != legs || ab-ba > 1 || ba-ab > 1 {
+						t.Errorf("%d teams, %d legs: %s hosts %s %d times and visits %d times", n, legs, a, b, ab, ba)
+					}
+				}
+			}
+			for _, team := range teams {
+				if h, want := home[team], (n-1)*legs; 2*h < want-1 || 2*h > want+1 {
+					t.Errorf("%d teams, %d legs: %s is at home %d times of %d", n, legs, team, h, want)
+				}
+			}
+		}
+	}
+}
+
+func TestKnockout(t *testing.T) {
+	s, err := Knockout(teamNames(6))
+	if err != nil {
+		t.Fatalf("Knockout returned error %v", err)
+	}
+	want := []Round{
+		{Number: 1,
+			Fixtures: []Fixture{
+				{Match: 1, Home: "Team D", Away: "Team E"},
+				{Match: 2, Home: "Team C", Away: "Team F"},
+			},
+			Byes: []string{"Team A", "Team B"},
+		},
+		{Number: 2,
+			Fixtures: []Fixture{
+				{Match: 3, Home: "Team A", AwayFrom: 1},
+				{Match: 4, Home: "Team B", AwayFrom: 2},
+			},
+		},
+		{Number: 3,
+			Fixtures: []Fixture{{Match: 5, HomeFrom: 3, AwayFrom: 4}},
+		},
+	}
+	if !reflect.DeepEqual(s.Rounds, want) {
+		t.Errorf("Knockout(6 teams) =\n%+v\nwant\n%+v", s.Rounds, want)
+	}
+
+	for n := 2; n <= 33; n++ {
+		s, err := Knockout(teamNames(n))
+		if err != nil {
+			t.Fatalf("Knockout(%d teams) returned error %v", n, err)
+		}
+		games, byes := 0, 0
+		for i, round := range s.Rounds {
+			games += len(round.Fixtures)
+			byes += len(round.Byes)
+			if i > 0 && len(round.Byes) > 0 {
+				t.Errorf("Knockout(%d teams): byes in round %d", n, round.Number)
+			}
+		}
+		if games != n-1 {
+			t.Errorf("Knockout(%d teams) has %d games, want %d", n, games, n-1)
+		}
+		last := s.Rounds[len(s.Rounds)-1]
+		if len(last.Fixtures) != 1 {
+			t.Errorf("Knockout(%d teams) ends with %d games", n, len(last.Fixtures))
+		}
+		if size := games + 1 + byes; size&(size-1) != 0 {
+			t.Errorf("Knockout(%d teams) has %d byes, which doesn't fill a bracket", n, byes)
+		}
+	}
+}
+
+func TestScheduleErrors(t *testing.T) {
+	for _, teams := range [][]string{
+		nil,
+		{"Ants"},
+		{"Ants", "Ants"},
+		{"Ants", ""},
+		{"Ants", " Bees"},
+		{"Ants", "Bees;Wasps"},
+		{"Ants", "# Bees"},
+	} {
+		if _, err := RoundRobin(teams, 1); err == nil {
+			t.Errorf("RoundRobin(%q) should have failed", teams)
+		}
+		if _, err := Knockout(teams); err == nil {
+			t.Errorf("Knockout(%q) should have failed", teams)
+		}
+	}
+	if _, err := RoundRobin(teamNames(4), 0); err == nil {
+		t.Error("RoundRobin with 0 legs should have failed")
+	}
+}
+
+func TestScheduleEncode(t *testing.T) {
+	s, err := RoundRobin([]string{"Ants", "Bees", "Cats"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	if err := s.Encode(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	want := `
+# Round 1
+Bees;Cats;unplayed
+# Ants: bye
+# Round 2
+Cats;Ants;unplayed
+# Bees: bye
+# Round 3
+Ants;Bees;unplayed
+# Cats: bye
+`[1:]
+	if buffer.String() != want {
+		t.Errorf("Encode =\n%s\nwant\n%s", buffer.String(), want)
+	}
+
+	// The plan reads as an empty table, and fills in as results are added.
+	var table bytes.Buffer
+	if err := Tally(strings.NewReader(want), &table); err != nil {
+		t.Fatalf("Tally of an unplayed schedule returned error %v", err)
+	}
+	if got := order(table.String()); len(got) != 0 {
+		t.Errorf("unplayed schedule has teams %v in the table", got)
+	}
+	played := strings.Replace(want, "Cats;Ants;unplayed", "Cats;Ants;0-2", 1)
+	table.Reset()
+	if err := Tally(strings.NewReader(played), &table); err != nil {
+		t.Fatalf("Tally of a part-played schedule returned error %v", err)
+	}
+	if got := order(table.String()); !reflect.DeepEqual(got, []string{"Ants", "Cats"}) {
+		t.Errorf("part-played schedule has teams %v", got)
+	}
+	// A result that has been lost, rather than not played yet, is still an
+	// error.
+	truncated := strings.Replace(played, "Ants;Bees;unplayed", "Ants;Bees;", 1)
+	if err := Tally(strings.NewReader(truncated), &table); err == nil || !strings.Contains(err.Error(), "line 8") {
+		t.Errorf("Tally of a schedule with a truncated line returned error %v, want one for line 8", err)
+	}
+
+	k, err := Knockout([]string{"Ants", "Bees", "Cats"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer.Reset()
+	if err := k.Encode(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	want = `
+# Round 1
+Bees;Cats;unplayed
+# Ants: bye
+# Round 2
+# Match 2: Ants v winner of match 1
+`[1:]
+	if buffer.String() != want {
+		t.Errorf("Encode of knockout =\n%s\nwant\n%s", buffer.String(), want)
+	}
+}
@@ -6,62 +6,242 @@ table of outcomes is then sorted by number of points in descending order.
 package tournament
 
 import (
-	"encoding/csv"
+	"bufio"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
 )
 
-type outcome int
+// Outcome is how a game went for one of the teams in it.
+type Outcome int
 
 const (
-	loss outcome = iota
-	draw
-	win
+	Loss Outcome = iota
+	Draw
+	Win
 )
 
+func (o Outcome) String() string {
+	switch o {
+	case Loss:
+		return "loss"
+	case Draw:
+		return "draw"
+	case Win:
+		return "win"
+	}
+	return fmt.Sprintf("Outcome(%d)", int(o))
+}
+
 type inputEntry struct {
 	teams    [2]string
-	outcomes [2]outcome
+	outcomes [2]Outcome
+	scored   bool   // whether the record gave the score, not just the outcome
+	goals    [2]int // goals scored by each team, if scored
 }
 
 type teamResult struct {
-	team   string
-	played int
-	wins   int
-	draws  int
-	losses int
-	points int
+	team         string
+	played       int
+	wins         int
+	draws        int
+	losses       int
+	goalsFor     int
+	goalsAgainst int
+	points       int
+}
+
+// goalDifference is how many more goals the team scored than it conceded.
+func (r teamResult) goalDifference() int {
+	return r.goalsFor - r.goalsAgainst
 }
 
 // Tally takes input strings describing the outcomes of games and returns a
-// table of those outcomes, sorted by total points earned.
+// table of those outcomes, sorted by total points earned. It scores games with
+// DefaultRules.
 func Tally(reader io.Reader, writer io.Writer) error {
+	return TallyWithRules(reader, writer, DefaultRules)
+}
+
+// TallyWithRules is Tally with the points for each game, and the order of teams
+// level on points, decided by rules.
+func TallyWithRules(reader io.Reader, writer io.Writer, rules Rules) error {
+	return TallyWithRenderer(reader, writer, rules, TextRenderer{})
+}
+
+// TallyWithRenderer is TallyWithRules with the standings written out by
+// renderer rather than as the usual text table.
+func TallyWithRenderer(reader io.Reader, writer io.Writer, rules Rules, renderer Renderer) error {
+	standings, err := ComputeStandings(reader, rules)
+	if err != nil {
+		return err
+	}
+	return renderer.Render(writer, standings)
+}
+
+// ComputeStandings reads games in the same way as Tally and returns the
+// standings they add up to, without writing anything.
+func ComputeStandings(reader io.Reader, rules Rules) (Standings, error) {
+	if err := rules.validate(); err != nil {
+		return Standings{}, err
+	}
+	entries, err := readEntries(reader)
+	if err != nil {
+		return Standings{}, err
+	}
+	return newStandings(tally(entries, rules), anyScored(entries)), nil
+}
+
+// readEntries reads one game per line in the form "home;away;outcome", where
+// the outcome is win, loss or draw from the home team's side, or in the form
+// "home;away;3-1" giving the score. The two forms can be mixed freely. Blank
+// lines, lines starting with #, and fixtures not played yet, written
+// "home;away;unplayed", are skipped.
+func readEntries(reader io.Reader) ([]inputEntry, error) {
 	var entries []inputEntry
-	csvReader := csv.NewReader(reader)
-	csvReader.Comma = ';'
-	csvReader.Comment = '#'
-	csvReader.FieldsPerRecord = -1 // Allow variable number of fields
-	for {
-		record, err := csvReader.Read()
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		entry, played, err := parseEntry(text)
+		if err != nil {
+			return nil, fmt.Errorf("tournament: line %d: %v", line, err)
+		}
+		if !played {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("tournament: reading input: %w", err)
+	}
+	return entries, nil
+}
+
+// parseEntry reads a single "home;away;outcome" or "home;away;3-1" record.
+// played is false for a "home;away;unplayed" fixture that has no result yet.
+func parseEntry(text string) (entry inputEntry, played bool, err error) {
+	record := strings.Split(text, ";")
+	if len(record) != 3 {
+		return inputEntry{}, false, fmt.Errorf("got %d fields in %q, want 3 separated by ';'", len(record), text)
+	}
+	t1, t2 := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+	switch {
+	case t1 == "" || t2 == "":
+		return inputEntry{}, false, fmt.Errorf("missing team name in %q", text)
+	case t1 == t2:
+		return inputEntry{}, false, fmt.Errorf("team %q cannot play itself", t1)
+	}
+
+	result := strings.TrimSpace(record[2])
+	switch result {
+	case "":
+		return inputEntry{}, false, fmt.Errorf("missing result in %q, want win, loss, draw, a score such as 3-1, or %s", text, unplayed)
+	case unplayed:
+		return inputEntry{}, false, nil
+	}
+	if result[0] >= '0' && result[0] <= '9' {
+		goals, err := parseScore(result)
 		if err != nil {
-			return err
+			return inputEntry{}, false, err
 		}
-		if len(record) == 3 {
-			t1, t2 := record[0], record[1]
-			teams := [2]string{t1, t2}
-			var outcomes [2]outcome
-			switch record[2] {
-			case "win":
-				outcomes = [2]outcome{win, loss}
-			case "loss":
-				outcomes = [2]outcome{loss, win}
-			case "draw":
-				outcomes = [2]outcome{draw, draw}
-			default:
-				return nil
+		return scoredEntry(t1, t2, goals), true, nil
+	}
+
+	var outcomes [2]Outcome
+	switch result {
+	case "win":
+		outcomes = [2]Outcome{Win, Loss}
+	case "loss":
+		outcomes = [2]Outcome{Loss, Win}
+	case "draw":
+		outcomes = [2]Outcome{Draw, Draw}
+	default:
+		return inputEntry{}, false, fmt.Errorf("unknown outcome %q, want win, loss or draw", record[2])
+	}
+	return inputEntry{teams: [2]string{t1, t2}, outcomes: outcomes}, true, nil
+}
+
+// unplayed is written in place of a result for a fixture that hasn't been
+// played yet.
+const unplayed = "unplayed"
+
+// parseScore reads a score such as "3-1", home goals first.
+func parseScore(score string) ([2]int, error) {
+	var goals [2]int
+	parts := strings.Split(score, "-")
+	if len(parts) != 2 {
+		return goals, fmt.Errorf("bad score %q, want home-away goals such as 3-1", score)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 || strings.HasPrefix(strings.TrimSpace(part), "+") {
+			return goals, fmt.Errorf("bad score %q, want home-away goals such as 3-1", score)
+		}
+		goals[i] = n
+	}
+	return goals, nil
+}
+
+// scoredEntry works out the outcome of a game from its score.
+func scoredEntry(home, away string, goals [2]int) inputEntry {
+	entry := inputEntry{teams: [2]string{home, away}, scored: true, goals: goals}
+	switch {
+	case goals[0] > goals[1]:
+		entry.outcomes = [2]Outcome{Win, Loss}
+	case goals[0] < goals[1]:
+		entry.outcomes = [2]Outcome{Loss, Win}
+	default:
+		entry.outcomes = [2]Outcome{Draw, Draw}
+	}
+	return entry
+}
+
+// anyScored reports whether any of the games came with a score.
+func anyScored(entries []inputEntry) bool {
+	for _, entry := range entries {
+		if entry.scored {
+			return true
+		}
+	}
+	return false
+}
+
+// tally adds up every team's games and returns the standings, ordered as rules
+// say.
+func tally(entries []inputEntry, rules Rules) []teamResult {
+	byTeam := map[string]*teamResult{}
+	for _, entry := range entries {
+		for i, team := range entry.teams {
+			result, ok := byTeam[team]
+			if !ok {
+				result = &teamResult{team: team}
+				byTeam[team] = result
+			}
+			result.played++
+			switch entry.outcomes[i] {
+			case Win:
+				result.wins++
+			case Draw:
+				result.draws++
+			case Loss:
+				result.losses++
 			}
-			entries = append(entries, inputEntry{teams: teams, outcomes: outcomes})
-		} else {
-			return nil
+			if entry.scored {
+				result.goalsFor += entry.goals[i]
+				result.goalsAgainst += entry.goals[1-i]
+			}
+			result.points += rules.points(entry, i)
 		}
 	}
+
+	results := make([]teamResult, 0, len(byTeam))
+	for _, result := range byTeam {
+		results = append(results, *result)
+	}
+	rules.rank(results, entries)
+	return results
 }
@@ -135,6 +135,93 @@ func TestTallyError(t *testing.T) {
 	}
 }
 
+func TestTallyErrorLines(t *testing.T) {
+	for _, tt := range []struct {
+		input, want string
+	}{
+		{"A;B;win\n\n# comment\nA;B;dra\n", "line 4: unknown outcome"},
+		{"A;B;win\nA;B\n", "line 2: got 2 fields"},
+		{"A;B;win;extra", "line 1: got 4 fields"},
+		{"A;;win", "line 1: missing team name"},
+		{"A;A;draw", `line 1: team "A" cannot play itself`},
+		{"A;B;win\nA;B;\n", "line 2: missing result"},
+		{"A;B;unplayed\nA;B; ", "line 2: missing result"},
+	} {
+		var buffer bytes.Buffer
+		err := Tally(strings.NewReader(tt.input), &buffer)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Tally(%q) error = %v, want one containing %q", tt.input, err, tt.want)
+		}
+		if buffer.Len() != 0 {
+			t.Errorf("Tally(%q) wrote output despite failing:\n%s", tt.input, buffer.String())
+		}
+	}
+}
+
+func TestTallyScores(t *testing.T) {
+	input := `
+Allegoric Alaskians;Blithering Badgers;3-1
+Devastating Donkeys;Courageous Californians;2-2
+Devastating Donkeys;Allegoric Alaskians;1-0
+# legacy records mix in, but add no goals
+Courageous Californians;Blithering Badgers;loss
+Blithering Badgers;Devastating Donkeys;0 - 4
+Allegoric Alaskians;Courageous Californians;10-0
+`
+	expected := `
+Team                           | MP |  W |  D |  L | GF | GA |  GD |  P
+Devastating Donkeys            |  3 |  2 |  1 |  0 |  7 |  2 |  +5 |  7
+Allegoric Alaskians            |  3 |  2 |  0 |  1 | 13 |  2 | +11 |  6
+Blithering Badgers             |  3 |  1 |  0 |  2 |  1 |  7 |  -6 |  3
+Courageous Californians        |  3 |  0 |  1 |  2 |  2 | 12 | -10 |  1
+`[1:]
+	var buffer bytes.Buffer
+	if err := Tally(strings.NewReader(input), &buffer); err != nil {
+		t.Fatalf("Tally returned error %v", err)
+	}
+	if buffer.String() != expected {
+		t.Errorf("Tally with scores =\n%s\nwant\n%s", buffer.String(), expected)
+	}
+
+	rules := DefaultRules
+	rules.TieBreakers = []TieBreaker{ByGoalDifference, ByName}
+	buffer.Reset()
+	if err := TallyWithRules(strings.NewReader(input), &buffer, rules); err != nil {
+		t.Fatalf("TallyWithRules returned error %v", err)
+	}
+	if got, want := order(buffer.String()), "Allegoric Alaskians,Devastating Donkeys,Blithering Badgers,Courageous Californians"; strings.Join(got, ",") != want {
+		t.Errorf("ordered by goal difference: %v, want %v", got, want)
+	}
+}
+
+func TestTallyScoreErrors(t *testing.T) {
+	for _, input := range []string{
+		"A;B;3-",
+		"A;B;3-1-2",
+		"A;B;3:1",
+		"A;B;1--1",
+		"A;B;1-x",
+		"A;B;1-+1",
+	} {
+		var buffer bytes.Buffer
+		err := Tally(strings.NewReader(input), &buffer)
+		if err == nil || !strings.Contains(err.Error(), "line 1: bad score") {
+			t.Errorf("Tally(%q) error = %v, want a bad score error", input, err)
+		}
+	}
+}
+
+func TestTallyEmpty(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Tally(strings.NewReader(""), &buffer); err != nil {
+		t.Fatalf("Tally of empty input returned error %v", err)
+	}
+	want := "Team                           | MP |  W |  D |  L |  P\n"
+	if buffer.String() != want {
+		t.Errorf("Tally of empty input = %q, want %q", buffer.String(), want)
+	}
+}
+
 func BenchmarkTally(b *testing.B) {
 	if testing.Short() {
 		b.Skip("skipping benchmark in short mode.")
@@ -0,0 +1,149 @@
+package tree
+
+import "sort"
+
+// RecordOf is a record for BuildFrom. Unlike Record, its IDs can be any
+// comparable type, need not be contiguous, and a parent's ID need not be lower
+// than its children's. The root is the record that is its own parent.
+type RecordOf[K comparable, T any] struct {
+	ID, Parent K
+	Payload    T
+}
+
+// NodeOf is a node of a tree built by BuildFrom, carrying its record's payload.
+type NodeOf[K comparable, T any] struct {
+	ID       K
+	Payload  T
+	Children []*NodeOf[K, T]
+}
+
+// BuildFrom builds a tree from records in any order. Each node's children are
+// put in order by less, if it is not nil, and otherwise kept in the order of
+// their records.
+//
+// It fails if two records share an ID, if there isn't exactly one root, if a
+// record's parent is missing, or if records form a cycle that can't be reached
+// from the root. The error is a *RecordError naming the IDs at fault.
+func BuildFrom[K comparable, T any](records []RecordOf[K, T], less func(a, b *NodeOf[K, T]) bool) (*NodeOf[K, T], error) {
+	return BuildFromWithOptions(records, less, Options{})
+}
+
+// BuildFromWithOptions is BuildFrom, checking the records as opts say.
+func BuildFromWithOptions[K comparable, T any](records []RecordOf[K, T], less func(a, b *NodeOf[K, T]) bool, opts Options) (*NodeOf[K, T], error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	found := problems{all: opts.CollectAll}
+	nodes := make([]NodeOf[K, T], len(records))
+	index := make(map[K]int, len(records))
+	var roots []K
+	for i, r := range records {
+		if _, ok := index[r.ID]; ok {
+			if !found.add(recordError(ErrDuplicateID, r.ID)) {
+				return nil, found.err()
+			}
+			continue
+		}
+		index[r.ID] = i
+		nodes[i] = NodeOf[K, T]{ID: r.ID, Payload: r.Payload}
+		if r.ID == r.Parent {
+			roots = append(roots, r.ID)
+		}
+	}
+	switch {
+	case len(roots) == 0:
+		if !found.add(recordError[K](ErrBadRoot)) {
+			return nil, found.err()
+		}
+	case len(roots) > 1:
+		if !found.add(recordError(ErrBadRoot, roots...)) {
+			return nil, found.err()
+		}
+	}
+
+	for i, r := range records {
+		if r.ID == r.Parent || index[r.ID] != i {
+			continue
+		}
+		p, ok := index[r.Parent]
+		if !ok {
+			if !found.add(recordError(ErrMissingParent, r.ID, r.Parent)) {
+				return nil, found.err()
+			}
+			continue
+		}
+		nodes[p].Children = append(nodes[p].Children, &nodes[i])
+	}
+
+	// Every record now hangs from its parent if it has one, so anything that
+	// can't be reached from the root is on, or hangs from, a cycle, a missing
+	// parent or another root.
+	var root *NodeOf[K, T]
+	reached := 0
+	if len(roots) > 0 {
+		root = &nodes[index[roots[0]]]
+		for it := []*NodeOf[K, T]{root}; len(it) > 0; {
+			n := it[len(it)-1]
+			it = it[:len(it)-1]
+			reached++
+			it = append(it, n.Children...)
+			if less != nil {
+				sort.SliceStable(n.Children, func(i, j int) bool { return less(n.Children[i], n.Children[j]) })
+			}
+		}
+	}
+	if reached < len(index) {
+		for _, cycle := range findCycles(records, index) {
+			if !found.add(recordError(ErrCycle, cycle...)) {
+				return nil, found.err()
+			}
+		}
+	}
+	if err := found.err(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// findCycles returns the IDs on each cycle of parents, in the order the records
+// that lead into them come. Records whose parents are missing, and duplicates,
+// are taken to lead nowhere.
+func findCycles[K comparable, T any](records []RecordOf[K, T], index map[K]int) [][]K {
+	// 0 is unvisited, 1 on the chain being followed, 2 known not to lead
+	// into a new cycle.
+	state := make([]int, len(records))
+	var cycles [][]K
+	for start := range records {
+		if index[records[start].ID] != start {
+			continue
+		}
+		var chain []int
+		i, ok := start, true
+		for ok && state[i] == 0 && records[i].ID != records[i].Parent {
+			state[i] = 1
+			chain = append(chain, i)
+			i, ok = index[records[i].Parent]
+		}
+		if ok && state[i] == 1 {
+			// The chain has come back on itself at i.
+			var cycle []K
+			for j := len(chain) - 1; ; j-- {
+				cycle = append(cycle, records[chain[j]].ID)
+				if chain[j] == i {
+					break
+				}
+			}
+			// Report it going from each record to its parent, starting
+			// from the first record met on it.
+			for l, r := 0, len(cycle)-1; l < r; l, r = l+1, r-1 {
+				cycle[l], cycle[r] = cycle[r], cycle[l]
+			}
+			cycles = append(cycles, cycle)
+		}
+		for _, j := range chain {
+			state[j] = 2
+		}
+	}
+	return cycles
+}
@@ -0,0 +1,160 @@
+package tree
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type employee struct {
+	name string
+}
+
+func TestBuildFrom(t *testing.T) {
+	records := []RecordOf[string, employee]{
+		{ID: "u-77", Parent: "u-12", Payload: employee{"Dana"}},
+		{ID: "u-12", Parent: "u-90", Payload: employee{"Kim"}},
+		{ID: "u-90", Parent: "u-90", Payload: employee{"Ari"}},
+		{ID: "u-05", Parent: "u-12", Payload: employee{"Bo"}},
+		{ID: "u-31", Parent: "u-90", Payload: employee{"Cy"}},
+	}
+	byName := func(a, b *NodeOf[string, employee]) bool { return a.Payload.name < b.Payload.name }
+	root, err := BuildFrom(records, byName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := renderOf(root); got != "Ari(Cy Kim(Bo Dana))" {
+		t.Errorf("BuildFrom ordered by name = %s", got)
+	}
+
+	// Without a comparator the records' order is kept.
+	root, err = BuildFrom(records, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := renderOf(root); got != "Ari(Kim(Dana Bo) Cy)" {
+		t.Errorf("BuildFrom in record order = %s", got)
+	}
+
+	if root, err := BuildFrom[int, string](nil, nil); root != nil || err != nil {
+		t.Errorf("BuildFrom(nil) = %v, %v", root, err)
+	}
+}
+
+func TestBuildFromIntIDs(t *testing.T) {
+	// Sparse IDs, and parents with higher IDs than their children.
+	records := []RecordOf[int64, struct{}]{
+		{ID: 1000, Parent: 1000},
+		{ID: 7, Parent: 900},
+		{ID: 900, Parent: 1000},
+		{ID: 3, Parent: 900},
+	}
+	root, err := BuildFrom(records, func(a, b *NodeOf[int64, struct{}]) bool { return a.ID < b.ID })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.ID != 1000 || len(root.Children) != 1 || root.Children[0].ID != 900 ||
+		root.Children[0].Children[0].ID != 3 || root.Children[0].Children[1].ID != 7 {
+		t.Errorf("BuildFrom built the wrong tree")
+	}
+}
+
+func TestBuildFromErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		records []RecordOf[string, int]
+		want    error
+		ids     []string
+	}{
+		{"duplicate", []RecordOf[string, int]{{ID: "a", Parent: "a"}, {ID: "b", Parent: "a"}, {ID: "b", Parent: "a"}},
+			ErrDuplicateID, []string{"b"}},
+		{"no root", []RecordOf[string, int]{{ID: "a", Parent: "b"}, {ID: "b", Parent: "a"}},
+			ErrBadRoot, nil},
+		{"two roots", []RecordOf[string, int]{{ID: "a", Parent: "a"}, {ID: "b", Parent: "b"}},
+			ErrBadRoot, []string{"a", "b"}},
+		{"orphan", []RecordOf[string, int]{{ID: "a", Parent: "a"}, {ID: "b", Parent: "x"}, {ID: "c", Parent: "b"}},
+			ErrMissingParent, []string{"b", "x"}},
+		{"cycle", []RecordOf[string, int]{
+			{ID: "a", Parent: "a"},
+			{ID: "tail", Parent: "c"},
+			{ID: "b", Parent: "a"},
+			{ID: "c", Parent: "d"},
+			{ID: "d", Parent: "e"},
+			{ID: "e", Parent: "c"},
+		}, ErrCycle, []string{"c", "d", "e"}},
+	} {
+		root, err := BuildFrom(tt.records, nil)
+		var recErr *RecordError[string]
+		if !errors.Is(err, tt.want) || !errors.As(err, &recErr) || !reflect.DeepEqual(recErr.IDs, tt.ids) {
+			t.Errorf("%s: BuildFrom = %v, %v, want %v with IDs %v", tt.name, root, err, tt.want, tt.ids)
+		}
+	}
+}
+
+func TestBuildFromCollectAll(t *testing.T) {
+	records := []RecordOf[string, int]{
+		{ID: "a", Parent: "a"},
+		{ID: "b", Parent: "x"},
+		{ID: "c", Parent: "d"},
+		{ID: "a", Parent: "c"},
+		{ID: "d", Parent: "c"},
+		{ID: "e", Parent: "y"},
+		{ID: "f", Parent: "f"},
+	}
+	_, err := BuildFromWithOptions(records, nil, Options{CollectAll: true})
+	var all *ValidationError
+	if !errors.As(err, &all) {
+		t.Fatalf("BuildFromWithOptions error = %v, want a *ValidationError", err)
+	}
+	want := []string{
+		"tree: duplicate ID: a",
+		"tree: bad root: a, f",
+		"tree: missing parent: b, x",
+		"tree: missing parent: e, y",
+		"tree: cycle: c, d",
+	}
+	if len(all.Errs) != len(want) {
+		t.Fatalf("got %d problems, want %d: %v", len(all.Errs), len(want), err)
+	}
+	for i, e := range all.Errs {
+		if e.Error() != want[i] {
+			t.Errorf("problem %d = %q, want %q", i, e, want[i])
+		}
+	}
+	for _, target := range []error{ErrDuplicateID, ErrBadRoot, ErrMissingParent, ErrCycle} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(err, %v) = false", target)
+		}
+	}
+	if errors.Is(err, ErrParentOrder) {
+		t.Errorf("errors.Is(err, ErrParentOrder) = true")
+	}
+
+	// Without CollectAll only the first problem comes back.
+	_, err = BuildFrom(records, nil)
+	if err == nil || err.Error() != want[0] {
+		t.Errorf("BuildFrom error = %v, want %q", err, want[0])
+	}
+	// And with nothing wrong, there's no error.
+	if _, err := BuildFromWithOptions(records[:1], nil, Options{CollectAll: true}); err != nil {
+		t.Errorf("BuildFromWithOptions of a lone root = %v", err)
+	}
+}
+
+// renderOf writes a tree as its payloads, with children in brackets.
+func renderOf(n *NodeOf[string, employee]) string {
+	var b strings.Builder
+	b.WriteString(n.Payload.name)
+	if len(n.Children) > 0 {
+		b.WriteByte('(')
+		for i, c := range n.Children {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(renderOf(c))
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The problems Build and BuildFrom can find with a set of records, and that
+// changes to a built tree, or writing it out, can run into. The errors returned
+// wrap these, so they can be told apart with errors.Is.
+var (
+	// ErrDuplicateID means more than one record has the same ID.
+	ErrDuplicateID = errors.New("tree: duplicate ID")
+	// ErrCycle means records are their own ancestors, or that a node was to
+	// be moved under itself.
+	ErrCycle = errors.New("tree: cycle")
+	// ErrMissingParent means a record's parent has no record of its own.
+	ErrMissingParent = errors.New("tree: missing parent")
+	// ErrBadRoot means there isn't exactly one root, that Build's root is not
+	// its own parent, or that a change to a tree would move or remove its root.
+	ErrBadRoot = errors.New("tree: bad root")
+	// ErrIDOutOfRange means Build was given an ID outside 0 to n-1, for n
+	// records.
+	ErrIDOutOfRange = errors.New("tree: ID out of range")
+	// ErrParentOrder means Build was given a record whose parent has a higher
+	// ID than it does.
+	ErrParentOrder = errors.New("tree: parent ID higher than child's")
+	// ErrNotFound means a change to a tree named a node that isn't in it.
+	ErrNotFound = errors.New("tree: no such node")
+	// ErrTooDeep means a tree is too deep to be written as nested JSON.
+	ErrTooDeep = errors.New("tree: too deep for nested JSON")
+)
+
+// RecordError is a problem with particular records. Build returns
+// *RecordError[int]; BuildFrom returns *RecordError[K] for its ID type.
+type RecordError[K comparable] struct {
+	// Err is one of the errors above, saying what is wrong.
+	Err error
+	// IDs are the records at fault. For ErrMissingParent they are the record
+	// and the parent it names; for ErrCycle, the records on the cycle, each
+	// followed by its parent. They are empty if no one record is to blame,
+	// as when there is no root at all.
+	IDs []K
+}
+
+func (e *RecordError[K]) Error() string {
+	if len(e.IDs) == 0 {
+		return e.Err.Error()
+	}
+	ids := make([]string, len(e.IDs))
+	for i, id := range e.IDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return fmt.Sprintf("%v: %s", e.Err, strings.Join(ids, ", "))
+}
+
+func (e *RecordError[K]) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError gathers every problem found with a set of records, when
+// Options.CollectAll asks for them all. errors.Is and errors.As look through
+// each of them.
+type ValidationError struct {
+	Errs []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("tree: %d problems with records: %s", len(e.Errs), strings.Join(msgs, "; "))
+}
+
+// Is reports whether any of the problems is target.
+func (e *ValidationError) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As sets target to the first of the problems that fits it.
+func (e *ValidationError) As(target interface{}) bool {
+	for _, err := range e.Errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Options change how BuildWithOptions and BuildFromWithOptions check records.
+type Options struct {
+	// CollectAll carries on checking after the first bad record, and reports
+	// everything wrong in one *ValidationError.
+	CollectAll bool
+}
+
+// problems keeps the errors found while checking records.
+type problems struct {
+	all  bool
+	errs []error
+}
+
+// add records a problem, and reports whether checking should go on.
+func (p *problems) add(err error) bool {
+	p.errs = append(p.errs, err)
+	return p.all
+}
+
+// err returns what was found, if anything.
+func (p *problems) err() error {
+	switch {
+	case len(p.errs) == 0:
+		return nil
+	case !p.all:
+		return p.errs[0]
+	}
+	return &ValidationError{Errs: p.errs}
+}
+
+// recordError is shorthand for a *RecordError.
+func recordError[K comparable](err error, ids ...K) error {
+	return &RecordError[K]{Err: err, IDs: ids}
+}
@@ -0,0 +1,78 @@
+package tree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuildErrors(t *testing.T) {
+	want := map[string]struct {
+		err error
+		ids []int
+	}{
+		"root node has parent":         {ErrBadRoot, []int{0}},
+		"no root node":                 {ErrBadRoot, nil},
+		"duplicate node":               {ErrDuplicateID, []int{1}},
+		"duplicate root":               {ErrDuplicateID, []int{0}},
+		"non-continuous":               {ErrIDOutOfRange, []int{4}},
+		"cycle directly":               {ErrCycle, []int{2}},
+		"cycle indirectly":             {ErrCycle, []int{2, 6, 3}},
+		"higher id parent of lower id": {ErrParentOrder, []int{1, 2}},
+	}
+	for _, tt := range failureTestCases {
+		_, err := Build(tt.input)
+		var recErr *RecordError[int]
+		w := want[tt.name]
+		if !errors.Is(err, w.err) || !errors.As(err, &recErr) || !reflect.DeepEqual(recErr.IDs, w.ids) {
+			t.Errorf("%s: Build error = %v, want %v with IDs %v", tt.name, err, w.err, w.ids)
+		}
+	}
+
+	for _, tt := range []struct {
+		records []Record
+		err     error
+	}{
+		{[]Record{{ID: 0}, {ID: -1, Parent: 0}}, ErrIDOutOfRange},
+		{[]Record{{ID: 0}, {ID: 1, Parent: -1}}, ErrMissingParent},
+		{[]Record{{ID: 0}, {ID: 1, Parent: 5}, {ID: 2}, {ID: 3}, {ID: 4}}, ErrMissingParent},
+		{[]Record{{ID: 0}, {ID: 1, Parent: 2}, {ID: 2, Parent: 1}}, ErrCycle},
+		{[]Record{{ID: 0}, {ID: 1, Parent: 2}, {ID: 2}}, ErrParentOrder},
+	} {
+		if _, err := Build(tt.records); !errors.Is(err, tt.err) {
+			t.Errorf("Build(%v) error = %v, want %v", tt.records, err, tt.err)
+		}
+	}
+}
+
+func TestBuildCollectAll(t *testing.T) {
+	records := []Record{
+		{ID: 0, Parent: 1},
+		{ID: 3, Parent: 3},
+		{ID: 7, Parent: 0},
+		{ID: 3, Parent: 0},
+		{ID: 2, Parent: 4},
+		{ID: 5, Parent: 2},
+		{ID: 1, Parent: 0},
+	}
+	_, err := BuildWithOptions(records, Options{CollectAll: true})
+	var all *ValidationError
+	if !errors.As(err, &all) {
+		t.Fatalf("BuildWithOptions error = %v, want a *ValidationError", err)
+	}
+	want := "tree: 5 problems with records: tree: bad root: 0; tree: cycle: 3; " +
+		"tree: ID out of range: 7; tree: duplicate ID: 3; tree: missing parent: 2, 4"
+	if err.Error() != want {
+		t.Errorf("BuildWithOptions error =\n%v\nwant\n%v", err, want)
+	}
+	var recErr *RecordError[int]
+	if !errors.As(err, &recErr) || recErr.Err != ErrBadRoot {
+		t.Errorf("errors.As found %v, want the bad root first", recErr)
+	}
+
+	for _, tt := range successTestCases {
+		if _, err := BuildWithOptions(tt.input, Options{CollectAll: true}); err != nil {
+			t.Errorf("%s: BuildWithOptions error = %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package tree
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// Flatten undoes Build, returning a record for every node in the tree ordered
+// by ID. The root's record has itself as its parent. Build takes the records
+// back as long as the tree is still as Build made it; once it has been changed
+// with Insert, Move or Delete, use Rebuild.
+func Flatten(root *Node) []Record {
+	if root == nil {
+		return nil
+	}
+	records := []Record{{ID: root.ID, Parent: root.ID}}
+	root.Walk(PreOrder, func(n *Node) bool {
+		for _, child := range n.Children {
+			records = append(records, Record{ID: child.ID, Parent: n.ID})
+		}
+		return true
+	})
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records
+}
+
+// MaxJSONDepth is how many levels deep a tree can be and still be written as
+// nested JSON. encoding/json refuses anything nested more than 10,000 deep, and
+// each level of a tree is an object holding an array. Deeper trees can be
+// stored as the flat records Flatten gives.
+const MaxJSONDepth = 5000
+
+// MarshalJSON writes the tree in nested form, each node as an object with its
+// ID and, unless it is a leaf, its children:
+//
+//	{"id":0,"children":[{"id":1},{"id":2}]}
+//
+// It fails with ErrTooDeep if the tree is more than MaxJSONDepth levels deep.
+func (n Node) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	// Each node is closed once all of its children have been written.
+	stack := []frame{{node: &n}}
+	buf.WriteString(`{"id":` + strconv.Itoa(n.ID))
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next < len(top.node.Children) {
+			if top.next == 0 {
+				buf.WriteString(`,"children":[`)
+			} else {
+				buf.WriteByte(',')
+			}
+			child := top.node.Children[top.next]
+			top.next++
+			buf.WriteString(`{"id":` + strconv.Itoa(child.ID))
+			stack = append(stack, frame{node: child})
+			if len(stack) > MaxJSONDepth {
+				return nil, recordError(ErrTooDeep, child.ID)
+			}
+			continue
+		}
+		if len(top.node.Children) > 0 {
+			buf.WriteByte(']')
+		}
+		buf.WriteByte('}')
+		stack = stack[:len(stack)-1]
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonNode is a node as it appears in JSON.
+type jsonNode struct {
+	ID       *int       `json:"id"`
+	Children []jsonNode `json:"children"`
+}
+
+// UnmarshalJSON reads a tree in the nested form MarshalJSON writes. The tree
+// has to pass the same checks as Build: every ID from 0 up to the number of
+// nodes appears exactly once, 0 is the root, and every child has a higher ID
+// than its parent. Children may be listed in any order, and come out sorted.
+// JSON null leaves n as it was. Trees changed with Insert, Move or Delete may
+// no longer pass these checks; read them with RebuildJSON instead.
+//
+// encoding/json rejects input nested too deeply before UnmarshalJSON is
+// called, so trees more than MaxJSONDepth levels deep can't be read this way
+// either.
+func (n *Node) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	records, err := jsonRecords(data)
+	if err != nil {
+		return err
+	}
+	built, err := Build(records)
+	if err != nil {
+		return err
+	}
+	*n = *built
+	return nil
+}
+
+// RebuildJSON reads a tree in the nested form MarshalJSON writes, checking it
+// as Rebuild does rather than as Build does, so any tree MarshalJSON wrote can
+// be read back however it was changed. JSON null gives a nil tree.
+func RebuildJSON(data []byte) (*Node, error) {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil, nil
+	}
+	records, err := jsonRecords(data)
+	if err != nil {
+		return nil, err
+	}
+	return Rebuild(records)
+}
+
+// jsonRecords turns a tree in nested JSON into records, the root's record
+// having itself as its parent.
+func jsonRecords(data []byte) ([]Record, error) {
+	var root jsonNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	type pending struct {
+		node   *jsonNode
+		parent int
+	}
+	stack := []pending{{&root, 0}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if p.node.ID == nil {
+			return nil, errors.New("tree: node without an id")
+		}
+		id := *p.node.ID
+		records = append(records, Record{ID: id, Parent: p.parent})
+		for i := range p.node.Children {
+			stack = append(stack, pending{&p.node.Children[i], id})
+		}
+	}
+	// The root is written as a child of itself.
+	records[0].Parent = records[0].ID
+	return records, nil
+}
+
+// Rebuild builds a tree from records such as Flatten gives for a tree changed
+// with Insert, Move or Delete. Unlike Build, it only needs the IDs to be
+// unique: they can have gaps, and a parent's ID can be higher than its
+// children's. The root is the record that is its own parent. It fails as
+// BuildFrom does, with a *RecordError[int].
+func Rebuild(records []Record) (*Node, error) {
+	generic := make([]RecordOf[int, struct{}], len(records))
+	for i, r := range records {
+		generic[i] = RecordOf[int, struct{}]{ID: r.ID, Parent: r.Parent}
+	}
+	root, err := BuildFrom(generic, func(a, b *NodeOf[int, struct{}]) bool { return a.ID < b.ID })
+	if err != nil || root == nil {
+		return nil, err
+	}
+
+	// Copy the tree over without recursing, so its depth doesn't matter.
+	nodes := make([]Node, 0, len(records))
+	type pending struct {
+		from *NodeOf[int, struct{}]
+		to   *Node
+	}
+	nodes = append(nodes, Node{ID: root.ID})
+	stack := []pending{{root, &nodes[0]}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, child := range p.from.Children {
+			nodes = append(nodes, Node{ID: child.ID})
+			to := &nodes[len(nodes)-1]
+			p.to.Children = append(p.to.Children, to)
+			stack = append(stack, pending{child, to})
+		}
+	}
+	return &nodes[0], nil
+}
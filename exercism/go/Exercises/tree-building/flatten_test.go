@@ -0,0 +1,175 @@
+package tree
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// randomRecords is a valid record set, in random order, for quick.Check.
+type randomRecords []Record
+
+func (randomRecords) Generate(rng *rand.Rand, size int) reflect.Value {
+	records := make([]Record, rng.Intn(size*10)+1)
+	for i := range records {
+		records[i].ID = i
+		if i > 0 {
+			records[i].Parent = rng.Intn(i)
+		}
+	}
+	rng.Shuffle(len(records), func(i, j int) { records[i], records[j] = records[j], records[i] })
+	return reflect.ValueOf(randomRecords(records))
+}
+
+func TestFlattenBuild(t *testing.T) {
+	roundTrip := func(records randomRecords) bool {
+		root, err := Build(records)
+		if err != nil {
+			t.Logf("Build: %v", err)
+			return false
+		}
+		flat := Flatten(root)
+		for i, r := range flat {
+			if r.ID != i {
+				t.Logf("Flatten gave %v out of order", flat)
+				return false
+			}
+		}
+		again, err := Build(flat)
+		return err == nil && reflect.DeepEqual(again, root)
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+
+	for _, tt := range successTestCases {
+		if tt.expected == nil {
+			continue
+		}
+		got, err := Build(Flatten(tt.expected))
+		if err != nil || !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: Build(Flatten(n)) = %v, %v", tt.name, got, err)
+		}
+	}
+	if got := Flatten(nil); got != nil {
+		t.Errorf("Flatten(nil) = %v", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	root := sampleTree(t)
+	data, err := json.Marshal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":0,"children":[{"id":1,"children":[{"id":3},{"id":4,"children":[{"id":6}]}]},{"id":2,"children":[{"id":5}]}]}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s\nwant %s", data, want)
+	}
+
+	// Children can come in any order; they are sorted as Build sorts them.
+	var got Node
+	shuffled := `{"id":0,"children":[{"id":2,"children":[{"id":5}]},{"id":1,"children":[{"id":4,"children":[{"id":6}]},{"id":3,"children":[]}]}]}`
+	if err := json.Unmarshal([]byte(shuffled), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, root) {
+		t.Errorf("Unmarshal = %v, want %v", got, root)
+	}
+
+	// Nodes nested in other values use the same form.
+	type wrapper struct {
+		Tree *Node `json:"tree"`
+	}
+	data, err = json.Marshal(wrapper{root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w wrapper
+	if err := json.Unmarshal(data, &w); err != nil || !reflect.DeepEqual(w.Tree, root) {
+		t.Errorf("round trip through a wrapper = %v, %v", w.Tree, err)
+	}
+
+	roundTrip := func(records randomRecords) bool {
+		root, _ := Build(records)
+		data, err := json.Marshal(root)
+		if err != nil {
+			return false
+		}
+		var got Node
+		return json.Unmarshal(data, &got) == nil && reflect.DeepEqual(&got, root)
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	for _, input := range []string{
+		`{"id":1}`,
+		`{"id":0,"children":[{"id":2}]}`,
+		`{"id":0,"children":[{"id":1},{"id":1}]}`,
+		`{"id":0,"children":[{"id":2,"children":[{"id":1}]}]}`,
+		`{"id":0,"children":[{"id":0}]}`,
+		`{"id":0,"children":[{}]}`,
+		`{"children":[]}`,
+		`[0]`,
+		`{"id":"0"}`,
+	} {
+		var n Node
+		if err := json.Unmarshal([]byte(input), &n); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want an error", input, n)
+		}
+	}
+	n := Node{ID: 7}
+	if err := json.Unmarshal([]byte("null"), &n); err != nil || n.ID != 7 {
+		t.Errorf("Unmarshal(null) = %v, %v, want the node left alone", n, err)
+	}
+}
+
+func TestJSONDepth(t *testing.T) {
+	chain := func(depth int) *Node {
+		records := make([]Record, depth)
+		for i := range records {
+			records[i] = Record{ID: i, Parent: i - 1}
+		}
+		records[0].Parent = 0
+		root, err := Build(records)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return root
+	}
+
+	// As deep as nested JSON allows round trips.
+	root := chain(MaxJSONDepth)
+	data, err := json.Marshal(root)
+	if err != nil {
+		t.Fatalf("Marshal of a tree %d deep: %v", MaxJSONDepth, err)
+	}
+	var got Node
+	if err := json.Unmarshal(data, &got); err != nil || !reflect.DeepEqual(&got, root) {
+		t.Errorf("round trip of a tree %d deep failed: %v", MaxJSONDepth, err)
+	}
+
+	// Deeper trees are refused, and round trip as flat records instead.
+	root = chain(20000)
+	if _, err := json.Marshal(root); !errors.Is(err, ErrTooDeep) {
+		t.Errorf("Marshal of a tree 20000 deep error = %v, want ErrTooDeep", err)
+	}
+	data, err = json.Marshal(Flatten(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Build(records)
+	if err != nil || !reflect.DeepEqual(again, root) {
+		t.Errorf("flat round trip of a tree 20000 deep = %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package tree
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DeleteStrategy says what Delete does with the children of the node it
+// removes.
+type DeleteStrategy int
+
+const (
+	// Cascade removes the node's children, and all below them, along with it.
+	Cascade DeleteStrategy = iota
+	// Reparent hands the node's children to its own parent.
+	Reparent
+)
+
+func (s DeleteStrategy) String() string {
+	switch s {
+	case Cascade:
+		return "cascade"
+	case Reparent:
+		return "reparent"
+	}
+	return fmt.Sprintf("DeleteStrategy(%d)", int(s))
+}
+
+// The changes below all keep each node's children sorted by ID, as Build
+// leaves them. They work on the tree in place, and fail with a *RecordError
+// naming the IDs involved. The root itself can't be moved or removed. A
+// changed tree may no longer follow Build's rules, so read its Flatten records
+// back with Rebuild, and its JSON with RebuildJSON.
+
+// Insert adds a new leaf with the given ID under the node parentID, and
+// returns it.
+func (n *Node) Insert(parentID, id int) (*Node, error) {
+	parent := n.Find(parentID)
+	if parent == nil {
+		return nil, recordError(ErrMissingParent, id, parentID)
+	}
+	if n.Find(id) != nil {
+		return nil, recordError(ErrDuplicateID, id)
+	}
+	child := &Node{ID: id}
+	addChild(parent, child)
+	return child, nil
+}
+
+// Move makes the node id, and everything below it, a child of newParentID. The
+// new parent can't be the node itself or anywhere below it.
+func (n *Node) Move(id, newParentID int) error {
+	node, parent, err := n.locate(id)
+	if err != nil {
+		return err
+	}
+	path := n.Path(newParentID)
+	if path == nil {
+		return recordError(ErrMissingParent, id, newParentID)
+	}
+	for _, p := range path {
+		if p == node {
+			return recordError(ErrCycle, id, newParentID)
+		}
+	}
+	removeChild(parent, node)
+	addChild(path[len(path)-1], node)
+	return nil
+}
+
+// Delete removes the node id from the tree, and deals with its children as
+// strategy says.
+func (n *Node) Delete(id int, strategy DeleteStrategy) error {
+	if strategy != Cascade && strategy != Reparent {
+		return fmt.Errorf("tree: unknown delete strategy %v", strategy)
+	}
+	node, parent, err := n.locate(id)
+	if err != nil {
+		return err
+	}
+	removeChild(parent, node)
+	if strategy == Reparent {
+		parent.Children = append(parent.Children, node.Children...)
+		sort.Sort(nodePart(parent.Children))
+		node.Children = nil
+	}
+	return nil
+}
+
+// Detach takes the node id and everything below it out of the tree, and
+// returns it as a tree of its own.
+func (n *Node) Detach(id int) (*Node, error) {
+	node, parent, err := n.locate(id)
+	if err != nil {
+		return nil, err
+	}
+	removeChild(parent, node)
+	return node, nil
+}
+
+// locate finds the node id and its parent, failing if it is missing or is the
+// root.
+func (n *Node) locate(id int) (node, parent *Node, err error) {
+	path := n.Path(id)
+	switch len(path) {
+	case 0:
+		return nil, nil, recordError(ErrNotFound, id)
+	case 1:
+		return nil, nil, recordError(ErrBadRoot, id)
+	}
+	return path[len(path)-1], path[len(path)-2], nil
+}
+
+// addChild puts child among parent's children, in ID order.
+func addChild(parent, child *Node) {
+	i := sort.Search(len(parent.Children), func(i int) bool { return parent.Children[i].ID >= child.ID })
+	parent.Children = append(parent.Children, nil)
+	copy(parent.Children[i+1:], parent.Children[i:])
+	parent.Children[i] = child
+}
+
+// removeChild takes child out of parent's children.
+func removeChild(parent, child *Node) {
+	for i, c := range parent.Children {
+		if c == child {
+			parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+			break
+		}
+	}
+	if len(parent.Children) == 0 {
+		// Leaves have nil children, as Build makes them.
+		parent.Children = nil
+	}
+}
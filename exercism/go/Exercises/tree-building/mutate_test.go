@@ -0,0 +1,255 @@
+package tree
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func rendered(t *testing.T, n *Node) string {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := n.Render(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.String()
+}
+
+func TestInsert(t *testing.T) {
+	root := sampleTree(t)
+	for _, id := range []int{9, 7, 8} {
+		if _, err := root.Insert(1, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	leaf, err := root.Insert(5, 10)
+	if err != nil || leaf.ID != 10 || root.Find(10) != leaf {
+		t.Fatalf("Insert(5, 10) = %v, %v", leaf, err)
+	}
+	want := `
+0
+├── 1
+│   ├── 3
+│   ├── 4
+│   │   └── 6
+│   ├── 7
+│   ├── 8
+│   └── 9
+└── 2
+    └── 5
+        └── 10
+`[1:]
+	if got := rendered(t, root); got != want {
+		t.Errorf("after inserting:\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := root.Insert(42, 11); !errors.Is(err, ErrMissingParent) {
+		t.Errorf("Insert under a missing parent: error = %v", err)
+	}
+	if _, err := root.Insert(0, 6); !errors.Is(err, ErrDuplicateID) {
+		t.Errorf("Insert of an existing ID: error = %v", err)
+	}
+}
+
+func TestMove(t *testing.T) {
+	root := sampleTree(t)
+	if err := root.Move(4, 2); err != nil {
+		t.Fatal(err)
+	}
+	want := `
+0
+├── 1
+│   └── 3
+└── 2
+    ├── 4
+    │   └── 6
+    └── 5
+`[1:]
+	if got := rendered(t, root); got != want {
+		t.Errorf("after moving 4 under 2:\n%s\nwant\n%s", got, want)
+	}
+
+	for _, tt := range []struct {
+		id, parent int
+		want       error
+		ids        []int
+	}{
+		{2, 6, ErrCycle, []int{2, 6}},
+		{4, 4, ErrCycle, []int{4, 4}},
+		{0, 3, ErrBadRoot, []int{0}},
+		{8, 3, ErrNotFound, []int{8}},
+		{3, 8, ErrMissingParent, []int{3, 8}},
+	} {
+		err := root.Move(tt.id, tt.parent)
+		var recErr *RecordError[int]
+		if !errors.Is(err, tt.want) || !errors.As(err, &recErr) || len(recErr.IDs) != len(tt.ids) || recErr.IDs[0] != tt.ids[0] {
+			t.Errorf("Move(%d, %d) error = %v, want %v with IDs %v", tt.id, tt.parent, err, tt.want, tt.ids)
+		}
+	}
+	if got := rendered(t, root); got != want {
+		t.Errorf("failed moves changed the tree:\n%s", got)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	root := sampleTree(t)
+	if err := root.Delete(1, Reparent); err != nil {
+		t.Fatal(err)
+	}
+	want := `
+0
+├── 2
+│   └── 5
+├── 3
+└── 4
+    └── 6
+`[1:]
+	if got := rendered(t, root); got != want {
+		t.Errorf("after deleting 1 with Reparent:\n%s\nwant\n%s", got, want)
+	}
+
+	if err := root.Delete(2, Cascade); err != nil {
+		t.Fatal(err)
+	}
+	if root.Find(5) != nil || root.Size() != 4 {
+		t.Errorf("after deleting 2 with Cascade:\n%s", rendered(t, root))
+	}
+	if err := root.Delete(6, Reparent); err != nil {
+		t.Fatal(err)
+	}
+	if n := root.Find(4); n.Children != nil {
+		t.Errorf("4 has children %v, want nil like any leaf", n.Children)
+	}
+
+	if err := root.Delete(0, Cascade); !errors.Is(err, ErrBadRoot) {
+		t.Errorf("Delete of the root: error = %v", err)
+	}
+	if err := root.Delete(6, Cascade); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete of a deleted node: error = %v", err)
+	}
+	if err := root.Delete(3, DeleteStrategy(9)); err == nil {
+		t.Error("Delete with an unknown strategy should fail")
+	}
+}
+
+func TestDetach(t *testing.T) {
+	root := sampleTree(t)
+	sub, err := root.Detach(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rendered(t, sub), "1\n├── 3\n└── 4\n    └── 6\n"; got != want {
+		t.Errorf("detached subtree:\n%s\nwant\n%s", got, want)
+	}
+	if got, want := rendered(t, root), "0\n└── 2\n    └── 5\n"; got != want {
+		t.Errorf("tree left behind:\n%s\nwant\n%s", got, want)
+	}
+	if _, err := root.Detach(0); !errors.Is(err, ErrBadRoot) {
+		t.Errorf("Detach of the root: error = %v", err)
+	}
+	if _, err := root.Detach(4); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Detach of a node no longer in the tree: error = %v", err)
+	}
+}
+
+func TestMutationsKeepChildrenSorted(t *testing.T) {
+	root := sampleTree(t)
+	steps := []func() error{
+		func() error { _, err := root.Insert(2, 7); return err },
+		func() error { return root.Move(1, 7) },
+		func() error { _, err := root.Insert(7, 8); return err },
+		func() error { return root.Delete(7, Reparent) },
+		func() error { return root.Move(3, 2) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		root.Walk(PreOrder, func(n *Node) bool {
+			for j := 1; j < len(n.Children); j++ {
+				if n.Children[j-1].ID >= n.Children[j].ID {
+					t.Errorf("step %d: children of %d out of order:\n%s", i, n.ID, rendered(t, root))
+					return false
+				}
+			}
+			return true
+		})
+	}
+	want := `
+0
+└── 2
+    ├── 1
+    │   └── 4
+    │       └── 6
+    ├── 3
+    ├── 5
+    └── 8
+`[1:]
+	if got := rendered(t, root); got != want {
+		t.Errorf("after all steps:\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestChangedTreeRoundTrip(t *testing.T) {
+	root, err := Build([]Record{{0, 0}, {1, 0}, {2, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := root.Move(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := root.Insert(1, 9); err != nil {
+		t.Fatal(err)
+	}
+	want := rendered(t, root)
+
+	// Build's rules no longer hold, so neither Build nor UnmarshalJSON takes
+	// the tree back, but Rebuild and RebuildJSON do.
+	if _, err := BuildWithOptions(Flatten(root), Options{CollectAll: true}); !errors.Is(err, ErrParentOrder) {
+		t.Errorf("Build(Flatten(root)) error = %v, want ErrParentOrder among them", err)
+	}
+	again, err := Rebuild(Flatten(root))
+	if err != nil {
+		t.Fatalf("Rebuild(Flatten(root)): %v", err)
+	}
+	if got := rendered(t, again); got != want {
+		t.Errorf("Rebuild(Flatten(root)) =\n%s\nwant\n%s", got, want)
+	}
+	data, err := json.Marshal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Node
+	if err := json.Unmarshal(data, &decoded); err == nil {
+		t.Errorf("Unmarshal(%s) = %v, want an error", data, decoded)
+	}
+	again, err = RebuildJSON(data)
+	if err != nil {
+		t.Fatalf("RebuildJSON(%s): %v", data, err)
+	}
+	if got := rendered(t, again); got != want {
+		t.Errorf("RebuildJSON round trip =\n%s\nwant\n%s", got, want)
+	}
+	for _, input := range []string{`{"id":1}`, `{"id":0,"children":[{"id":2,"children":[{"id":1}]}]}`} {
+		if _, err := RebuildJSON([]byte(input)); err != nil {
+			t.Errorf("RebuildJSON(%s) error = %v", input, err)
+		}
+	}
+	for _, input := range []string{`{"id":0,"children":[{"id":1},{"id":1}]}`, `{"id":0,"children":[{}]}`, `[0]`} {
+		if _, err := RebuildJSON([]byte(input)); err == nil {
+			t.Errorf("RebuildJSON(%s) succeeded, want an error", input)
+		}
+	}
+	if got, err := RebuildJSON([]byte("null")); got != nil || err != nil {
+		t.Errorf("RebuildJSON(null) = %v, %v", got, err)
+	}
+
+	if got, err := Rebuild(nil); got != nil || err != nil {
+		t.Errorf("Rebuild(nil) = %v, %v", got, err)
+	}
+	var rerr *RecordError[int]
+	if _, err := Rebuild([]Record{{5, 5}, {7, 5}, {7, 5}}); !errors.As(err, &rerr) || !errors.Is(err, ErrDuplicateID) {
+		t.Errorf("Rebuild with a duplicate: error = %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package tree
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Order is an order in which to visit the nodes of a tree.
+type Order int
+
+const (
+	// PreOrder visits each node before its children.
+	PreOrder Order = iota
+	// PostOrder visits each node after its children.
+	PostOrder
+	// LevelOrder visits the root, then its children, then their children,
+	// and so on, a level at a time.
+	LevelOrder
+)
+
+func (o Order) String() string {
+	switch o {
+	case PreOrder:
+		return "pre-order"
+	case PostOrder:
+		return "post-order"
+	case LevelOrder:
+		return "level-order"
+	}
+	return fmt.Sprintf("Order(%d)", int(o))
+}
+
+// None of the code below recurses: every walk keeps its own stack or queue on
+// the heap, so a tree as deep as a linked list is no trouble.
+
+// frame is a node on a walk's stack, with the index of the next child to go
+// into.
+type frame struct {
+	node *Node
+	next int
+}
+
+// Iterator steps through the nodes of a tree one at a time:
+//
+//	for it := root.Iterate(tree.PreOrder); it.Next(); {
+//		n := it.Node()
+//		...
+//	}
+type Iterator struct {
+	order   Order
+	current *Node
+	pending []*Node // nodes still to visit, for pre- and level-order
+	stack   []frame // the path down to the next node, for post-order
+}
+
+// Iterate returns an iterator over the tree in the given order. It panics if
+// the order is not one of PreOrder, PostOrder or LevelOrder.
+func (n *Node) Iterate(order Order) *Iterator {
+	it := &Iterator{order: order}
+	if n == nil {
+		return it
+	}
+	switch order {
+	case PreOrder, LevelOrder:
+		it.pending = []*Node{n}
+	case PostOrder:
+		it.stack = []frame{{node: n}}
+	default:
+		panic(fmt.Sprintf("tree: unknown order %v", order))
+	}
+	return it
+}
+
+// Next moves to the next node, and reports whether there was one.
+func (it *Iterator) Next() bool {
+	switch it.order {
+	case PreOrder:
+		if len(it.pending) == 0 {
+			it.current = nil
+			return false
+		}
+		// pending is a stack here: push the children last first so the
+		// first child comes off next.
+		last := len(it.pending) - 1
+		it.current = it.pending[last]
+		it.pending = it.pending[:last]
+		for i := len(it.current.Children) - 1; i >= 0; i-- {
+			it.pending = append(it.pending, it.current.Children[i])
+		}
+		return true
+	case LevelOrder:
+		if len(it.pending) == 0 {
+			it.current = nil
+			return false
+		}
+		// pending is a queue here.
+		it.current = it.pending[0]
+		it.pending[0] = nil
+		it.pending = append(it.pending[1:], it.current.Children...)
+		return true
+	}
+
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		if top.next < len(top.node.Children) {
+			child := top.node.Children[top.next]
+			top.next++
+			it.stack = append(it.stack, frame{node: child})
+			continue
+		}
+		it.current = top.node
+		it.stack = it.stack[:len(it.stack)-1]
+		return true
+	}
+	it.current = nil
+	return false
+}
+
+// Node returns the node Next moved to.
+func (it *Iterator) Node() *Node {
+	return it.current
+}
+
+// Walk calls visit for each node of the tree in the given order, stopping
+// early if visit returns false.
+func (n *Node) Walk(order Order, visit func(*Node) bool) {
+	for it := n.Iterate(order); it.Next(); {
+		if !visit(it.Node()) {
+			return
+		}
+	}
+}
+
+// Size returns the number of nodes in the tree.
+func (n *Node) Size() int {
+	size := 0
+	n.Walk(LevelOrder, func(*Node) bool {
+		size++
+		return true
+	})
+	return size
+}
+
+// Find returns the node with the given ID, or nil if there is none.
+func (n *Node) Find(id int) *Node {
+	var found *Node
+	n.Walk(PreOrder, func(node *Node) bool {
+		if node.ID == id {
+			found = node
+		}
+		return found == nil
+	})
+	return found
+}
+
+// Path returns the nodes from the root down to the one with the given ID, or
+// nil if there is none.
+func (n *Node) Path(id int) []*Node {
+	if n == nil {
+		return nil
+	}
+	// A depth-first walk's stack is always the path to the node on top.
+	stack := []frame{{node: n}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next == 0 && top.node.ID == id {
+			path := make([]*Node, len(stack))
+			for i, f := range stack {
+				path[i] = f.node
+			}
+			return path
+		}
+		if top.next < len(top.node.Children) {
+			child := top.node.Children[top.next]
+			top.next++
+			stack = append(stack, frame{node: child})
+			continue
+		}
+		stack = stack[:len(stack)-1]
+	}
+	return nil
+}
+
+// Depth returns how many levels below the root the node with the given ID is,
+// 0 for the root itself, or -1 if there is no such node.
+func (n *Node) Depth(id int) int {
+	return len(n.Path(id)) - 1
+}
+
+// LowestCommonAncestor returns the deepest node that has both the nodes with
+// the given IDs below it, counting a node as below itself. It returns nil if
+// either ID is not in the tree.
+func (n *Node) LowestCommonAncestor(a, b int) *Node {
+	pathA, pathB := n.Path(a), n.Path(b)
+	if pathA == nil || pathB == nil {
+		return nil
+	}
+	var lca *Node
+	for i := 0; i < len(pathA) && i < len(pathB) && pathA[i] == pathB[i]; i++ {
+		lca = pathA[i]
+	}
+	return lca
+}
+
+// Render writes the tree out one node per line, with lines drawn to show
+// which nodes are whose children:
+//
+//	0
+//	├── 1
+//	│   └── 3
+//	└── 2
+func (n *Node) Render(writer io.Writer) error {
+	w := bufio.NewWriter(writer)
+	if n == nil {
+		return w.Flush()
+	}
+	type line struct {
+		node   *Node
+		prefix string // what goes before the connector
+		last   bool   // whether node is the last of its parent's children
+	}
+	fmt.Fprintln(w, n.ID)
+	stack := make([]line, 0, len(n.Children))
+	push := func(parent *Node, prefix string) {
+		for i := len(parent.Children) - 1; i >= 0; i-- {
+			stack = append(stack, line{parent.Children[i], prefix, i == len(parent.Children)-1})
+		}
+	}
+	push(n, "")
+	for len(stack) > 0 {
+		l := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		connector, indent := "├── ", "│   "
+		if l.last {
+			connector, indent = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%d\n", l.prefix, connector, l.node.ID)
+		push(l.node, l.prefix+indent)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,187 @@
+package tree
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sampleTree is
+//
+//	0
+//	├── 1
+//	│   ├── 3
+//	│   └── 4
+//	│       └── 6
+//	└── 2
+//	    └── 5
+func sampleTree(t *testing.T) *Node {
+	t.Helper()
+	root, err := Build([]Record{
+		{ID: 6, Parent: 4},
+		{ID: 5, Parent: 2},
+		{ID: 4, Parent: 1},
+		{ID: 3, Parent: 1},
+		{ID: 2, Parent: 0},
+		{ID: 1, Parent: 0},
+		{ID: 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func ids(nodes []*Node) []int {
+	var ids []int
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+func TestOrders(t *testing.T) {
+	root := sampleTree(t)
+	for _, tt := range []struct {
+		order Order
+		want  []int
+	}{
+		{PreOrder, []int{0, 1, 3, 4, 6, 2, 5}},
+		{PostOrder, []int{3, 6, 4, 1, 5, 2, 0}},
+		{LevelOrder, []int{0, 1, 2, 3, 4, 5, 6}},
+	} {
+		var walked []*Node
+		root.Walk(tt.order, func(n *Node) bool {
+			walked = append(walked, n)
+			return true
+		})
+		if got := ids(walked); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Walk(%v) visited %v, want %v", tt.order, got, tt.want)
+		}
+
+		var iterated []*Node
+		for it := root.Iterate(tt.order); it.Next(); {
+			iterated = append(iterated, it.Node())
+		}
+		if got := ids(iterated); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Iterate(%v) gave %v, want %v", tt.order, got, tt.want)
+		}
+
+		// Stopping early visits only a prefix.
+		var first []*Node
+		root.Walk(tt.order, func(n *Node) bool {
+			first = append(first, n)
+			return len(first) < 3
+		})
+		if got := ids(first); !reflect.DeepEqual(got, tt.want[:3]) {
+			t.Errorf("Walk(%v) stopped after %v, want %v", tt.order, got, tt.want[:3])
+		}
+	}
+}
+
+func TestQueries(t *testing.T) {
+	root := sampleTree(t)
+	if got := root.Size(); got != 7 {
+		t.Errorf("Size = %d, want 7", got)
+	}
+	if got := root.Find(4); got == nil || got.ID != 4 || len(got.Children) != 1 {
+		t.Errorf("Find(4) = %v", got)
+	}
+	if got := root.Find(7); got != nil {
+		t.Errorf("Find(7) = %v, want nil", got)
+	}
+	if got := ids(root.Path(6)); !reflect.DeepEqual(got, []int{0, 1, 4, 6}) {
+		t.Errorf("Path(6) = %v", got)
+	}
+	if got := root.Path(7); got != nil {
+		t.Errorf("Path(7) = %v, want nil", got)
+	}
+	for id, want := range map[int]int{0: 0, 2: 1, 6: 3, 7: -1} {
+		if got := root.Depth(id); got != want {
+			t.Errorf("Depth(%d) = %d, want %d", id, got, want)
+		}
+	}
+	for _, tt := range []struct{ a, b, want int }{
+		{6, 3, 1},
+		{6, 5, 0},
+		{4, 6, 4},
+		{2, 2, 2},
+		{0, 5, 0},
+	} {
+		if got := root.LowestCommonAncestor(tt.a, tt.b); got == nil || got.ID != tt.want {
+			t.Errorf("LowestCommonAncestor(%d, %d) = %v, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if got := root.LowestCommonAncestor(1, 9); got != nil {
+		t.Errorf("LowestCommonAncestor(1, 9) = %v, want nil", got)
+	}
+}
+
+func TestNilTree(t *testing.T) {
+	var root *Node
+	if root.Size() != 0 || root.Find(0) != nil || root.Path(0) != nil || root.Depth(0) != -1 {
+		t.Error("queries on a nil tree should find nothing")
+	}
+	if root.Iterate(PostOrder).Next() {
+		t.Error("iterating a nil tree should give nothing")
+	}
+	var buffer bytes.Buffer
+	if err := root.Render(&buffer); err != nil || buffer.Len() != 0 {
+		t.Errorf("Render of a nil tree wrote %q, %v", buffer.String(), err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := sampleTree(t).Render(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	want := `
+0
+├── 1
+│   ├── 3
+│   └── 4
+│       └── 6
+└── 2
+    └── 5
+`[1:]
+	if buffer.String() != want {
+		t.Errorf("Render =\n%s\nwant\n%s", buffer.String(), want)
+	}
+}
+
+func TestDeepTree(t *testing.T) {
+	// A chain this long would overflow the stack if anything recursed.
+	const depth = 1 << 18
+	records := make([]Record, depth)
+	for i := range records {
+		records[i] = Record{ID: i, Parent: i - 1}
+	}
+	records[0].Parent = 0
+	root, err := Build(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := root.Size(); got != depth {
+		t.Errorf("Size = %d, want %d", got, depth)
+	}
+	if got := root.Depth(depth - 1); got != depth-1 {
+		t.Errorf("Depth of the bottom = %d, want %d", got, depth-1)
+	}
+	if got := root.LowestCommonAncestor(depth-1, depth-2); got == nil || got.ID != depth-2 {
+		t.Errorf("LowestCommonAncestor at the bottom = %v", got)
+	}
+	it := root.Iterate(PostOrder)
+	if !it.Next() || it.Node().ID != depth-1 {
+		t.Errorf("post-order starts at %v, want the bottom", it.Node())
+	}
+
+	var buffer bytes.Buffer
+	if err := root.Find(depth - 3).Render(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(buffer.String(), "\n"); got != 3 {
+		t.Errorf("Render of the last three nodes wrote %d lines", got)
+	}
+}
@@ -8,7 +8,6 @@ the list of vertices is sorted.
 package tree
 
 import (
-	"errors"
 	"sort"
 )
 
@@ -40,55 +39,97 @@ func (node nodePart) Less(i, j int) bool {
 	return node[i].ID < node[j].ID
 }
 
-// Build takes an array of records and sorts them into a proper tree
+// Build takes an array of records and sorts them into a proper tree. Any
+// error is a *RecordError[int] wrapping one of ErrIDOutOfRange,
+// ErrDuplicateID, ErrBadRoot, ErrCycle, ErrMissingParent or ErrParentOrder.
 func Build(records []Record) (*Node, error) {
+	return BuildWithOptions(records, Options{})
+}
+
+// BuildWithOptions is Build, checking the records as opts say.
+func BuildWithOptions(records []Record, opts Options) (*Node, error) {
 
 	if len(records) <= 0 {
 		return nil, nil
 	}
 
-	parentNode := make([]*Node, len(records)) // Why does this have to be a pointer...
-	childNode := make([]Node, len(records))   // and this doesn't...
+	childNode := make([]Node, len(records))
+	parents := make([]int, len(records))
 	alreadyKnown := make([]bool, len(records))
+	found := problems{all: opts.CollectAll}
+
+	// Without a record for ID 0 there is no root at all, which is the first
+	// thing to know about such records
+	if !hasRoot(records) && !found.add(recordError[int](ErrBadRoot)) {
+		return nil, found.err()
+	}
+
+	// The records that pass checkRecord, to look for missing parents and
+	// cycles among once every ID has been seen
+	var good []RecordOf[int, struct{}]
 
 	// error handling requires ranging _, err := range records { ... }
 	for _, record := range records {
 
-		// We can't have more IDs than we have records
-		if record.ID >= len(records) {
-			return nil, errors.New("record list is missing a node")
+		err := checkRecord(record, alreadyKnown)
+
+		// Track if we are aware of any given node, even a bad one, so that
+		// any duplicates of it are still caught
+		if record.ID >= 0 && record.ID < len(records) {
+			alreadyKnown[record.ID] = true
 		}
+		if err != nil {
+			if !found.add(err) {
+				return nil, found.err()
+			}
+			continue
+		}
+		parents[record.ID] = record.Parent
+		good = append(good, RecordOf[int, struct{}]{ID: record.ID, Parent: record.Parent})
+	}
 
-		// We can't have a ID with Parent <= ID UNLESS ID == 0
-		if record.ID != 0 && record.ID <= record.Parent {
-			return nil, errors.New("a nonroot record has self or child as own ancestor")
+	// A parent has to be one of the IDs seen above
+	known := func(id int) bool { return id < len(alreadyKnown) && alreadyKnown[id] }
+	for _, record := range good {
+		if !known(record.Parent) && !found.add(recordError(ErrMissingParent, record.ID, record.Parent)) {
+			return nil, found.err()
 		}
+	}
 
-		// We can't have duplicate IDs
-		if alreadyKnown[record.ID] {
-			return nil, errors.New("can't have the same ID multiple times")
+	// Following parents from any record has to end at the root
+	index := make(map[int]int, len(good))
+	for i, record := range good {
+		index[record.ID] = i
+	}
+	onCycle := map[int]bool{}
+	for _, cycle := range findCycles(good, index) {
+		for _, id := range cycle {
+			onCycle[id] = true
+		}
+		if !found.add(recordError(ErrCycle, cycle...)) {
+			return nil, found.err()
 		}
+	}
 
-		// Track if we are aware of any given node
-		alreadyKnown[record.ID] = true
-
-		// Populate our parentNode at any given ID
-		// The record.Parent of any given parentNode[i] is
-		// the childNode[j]'s record.Parent; don't copy this information into
-		// the array; use a pointer. When in doubt, use a pointer.
-		if record.ID != 0 {
-			parentNode[record.ID] = &childNode[record.Parent]
-		} else if record.Parent != 0 {
-			// A kill switch on a malformed root record
-			return nil, errors.New("root record has nonzero Parent")
+	// And on top of that, Build wants parents to come before their children,
+	// which records on a cycle, or missing their parents, have been told
+	// about already
+	for _, record := range good {
+		if record.Parent > record.ID && known(record.Parent) && !onCycle[record.ID] {
+			if !found.add(recordError(ErrParentOrder, record.ID, record.Parent)) {
+				return nil, found.err()
+			}
 		}
 	}
+	if err := found.err(); err != nil {
+		return nil, err
+	}
 
-	// Iterate over the records, appending child nodes to our list of children
-	// It's important that we start at not the first record! What does
-	// childNode[0] even mean!
+	// Iterate over the records, appending child nodes to their parent's list
+	// of children. It's important that we start at not the first record! What
+	// does childNode[0] even mean!
 	for i := 1; i < len(records); i++ {
-		parentNode[i].Children = append(parentNode[i].Children, &childNode[i])
+		childNode[parents[i]].Children = append(childNode[parents[i]].Children, &childNode[i])
 	}
 
 	// We have now a Node which contains the arbitrarily organized children
@@ -102,4 +143,38 @@ func Build(records []Record) (*Node, error) {
 	return &childNode[0], nil // childNode[0] == whole tree
 }
 
+// hasRoot reports whether any of records is for ID 0.
+func hasRoot(records []Record) bool {
+	for _, record := range records {
+		if record.ID == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRecord checks one record against the rules Build needs that don't
+// depend on the other records, given which IDs have been seen already.
+func checkRecord(record Record, alreadyKnown []bool) error {
+	switch {
+	// We can't have more IDs than we have records
+	case record.ID < 0 || record.ID >= len(alreadyKnown):
+		return recordError(ErrIDOutOfRange, record.ID)
+	// We can't have duplicate IDs
+	case alreadyKnown[record.ID]:
+		return recordError(ErrDuplicateID, record.ID)
+	// A kill switch on a malformed root record
+	case record.ID == 0 && record.Parent != 0:
+		return recordError(ErrBadRoot, record.ID)
+	case record.ID == 0:
+		return nil
+	// We can't have a ID with Parent <= ID UNLESS ID == 0
+	case record.Parent == record.ID:
+		return recordError(ErrCycle, record.ID)
+	case record.Parent < 0:
+		return recordError(ErrMissingParent, record.ID, record.Parent)
+	}
+	return nil
+}
+
 // https://ieftimov.com/post/golang-datastructures-trees/